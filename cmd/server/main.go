@@ -1,96 +1,526 @@
 package main
 
 import (
+    "context"
+    "crypto/tls"
     "database/sql"
+    "errors"
+    "flag"
     "fmt"
-    "log"
+    "io/fs"
+    "net"
     "os"
     "os/signal"
+    "strings"
     "syscall"
-    
+    "time"
     
     "github.com/gofiber/fiber/v2"
     "github.com/gofiber/fiber/v2/middleware/cors"
-    _ "github.com/lib/pq"
+    "github.com/gofiber/fiber/v2/middleware/helmet"
     "go.uber.org/zap"
     
     "github.com/adityaK87/go-backend-assignment/config"
+    "github.com/adityaK87/go-backend-assignment/db/migrations"
+    "github.com/adityaK87/go-backend-assignment/internal/accesslog"
+    "github.com/adityaK87/go-backend-assignment/internal/auth"
+    "github.com/adityaK87/go-backend-assignment/internal/certs"
+    "github.com/adityaK87/go-backend-assignment/internal/clientip"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/graph"
+    "github.com/adityaK87/go-backend-assignment/internal/grpcapi"
     "github.com/adityaK87/go-backend-assignment/internal/handler"
+    "github.com/adityaK87/go-backend-assignment/internal/health"
+    "github.com/adityaK87/go-backend-assignment/internal/lifecycle"
     "github.com/adityaK87/go-backend-assignment/internal/logger"
     "github.com/adityaK87/go-backend-assignment/internal/middleware"
+    "github.com/adityaK87/go-backend-assignment/internal/outbox"
+    "github.com/adityaK87/go-backend-assignment/internal/pgnotify"
     "github.com/adityaK87/go-backend-assignment/internal/repository"
     "github.com/adityaK87/go-backend-assignment/internal/routes"
     "github.com/adityaK87/go-backend-assignment/internal/service"
+    "github.com/adityaK87/go-backend-assignment/internal/sse"
+    "github.com/adityaK87/go-backend-assignment/internal/version"
+    "github.com/adityaK87/go-backend-assignment/internal/webhook"
 )
 
 func main() {
+    os.Exit(run())
+}
+
+// run returns the process exit code: 0 after a clean shutdown, 1 when
+// startup, a component or the shutdown itself failed, and 2 for invalid
+// configuration. Everything started is stopped on every path.
+func run() int {
     // Load configuration
-    cfg := config.Load()
+    cfg, opts, err := config.Load(os.Args[1:])
+    if errors.Is(err, flag.ErrHelp) {
+        return lifecycle.ExitOK
+    }
+    if err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        return 2
+    }
+    if opts.Version {
+        fmt.Println(version.Get())
+        return lifecycle.ExitOK
+    }
+    if opts.PrintConfig {
+        if err := cfg.Print(os.Stdout); err != nil {
+            fmt.Fprintln(os.Stderr, "Failed to print configuration:", err)
+            return lifecycle.ExitFailure
+        }
+        return lifecycle.ExitOK
+    }
     
     // Initialize logger
-    if err := logger.Init(); err != nil {
-        log.Fatal("Failed to initialize logger:", err)
+    if err := logger.Init(logger.Config{
+        Level:              cfg.Logging.Level,
+        Encoding:           cfg.Logging.Format,
+        OutputPaths:        cfg.Logging.OutputPaths,
+        SamplingInitial:    cfg.Logging.Sampling.Initial,
+        SamplingThereafter: cfg.Logging.Sampling.Thereafter,
+        StacktraceLevel:    cfg.Logging.StacktraceLevel,
+        PackageLevels:      cfg.Logging.PackageLevels,
+    }); err != nil {
+        fmt.Fprintln(os.Stderr, "Failed to initialize logger:", err)
+        return lifecycle.ExitFailure
     }
-    defer logger.Sync()
+    build := version.Get()
+    logger.Log.Info("Build info",
+        zap.String("version", build.Version),
+        zap.String("commit", build.Commit),
+        zap.String("build_time", build.BuildTime),
+        zap.Bool("dirty", build.Dirty),
+        zap.String("go_version", build.GoVersion),
+    )
     
-
+    // Components stop in reverse order: servers drain first, the logger is
+    // flushed last
+    lc := lifecycle.New(lifecycle.Config{
+        PreStopDelay:    cfg.Server.PreStopDelay,
+        ShutdownTimeout: cfg.Server.ShutdownTimeout,
+    }, logger.Log)
+    lc.Register("logger", func(context.Context) error {
+        logger.Sync()
+        return nil
+    })
+    // SIGUSR1 toggles debug logging
+    lc.Go("log level signal", logger.WatchLevelSignal)
     
-    // Connect to database
-    db, err := sql.Open("postgres", cfg.DatabaseURL)
+    fail := func(msg string, err error) int {
+        logger.Log.WithOptions(zap.AddCallerSkip(1)).Error(msg, zap.Error(err))
+        lc.Shutdown(err)
+        return lifecycle.ExitFailure
+    }
+    
+    accessLog, err := accesslog.New(accesslog.Config{
+        Format:        cfg.AccessLog.Format,
+        Output:        cfg.AccessLog.Output,
+        Fields:        cfg.AccessLog.Fields,
+        MaxSizeMB:     cfg.AccessLog.Rotation.MaxSizeMB,
+        MaxAge:        cfg.AccessLog.Rotation.MaxAge,
+        MaxBackups:    cfg.AccessLog.Rotation.MaxBackups,
+        Compress:      cfg.AccessLog.Rotation.Compress,
+        CaptureBodies: cfg.AccessLog.CaptureBodies,
+        MaxBodyBytes:  cfg.AccessLog.MaxBodyBytes,
+    })
     if err != nil {
-        logger.Log.Fatal("Failed to connect to database", zap.Error(err))
+        return fail("Failed to open access log", err)
+    }
+    lc.Register("access log", func(context.Context) error {
+        return accessLog.Close()
+    })
+    
+    // Started before the servers so it outlives them and flushes whatever
+    // they reported while draining
+    reporter, err := startErrorReporter(lc, cfg.ErrorReporting)
+    if err != nil {
+        return fail("Failed to open error report sink", err)
     }
-    defer db.Close()
     
-    // Verify database connection
-    if err := db.Ping(); err != nil {
-        logger.Log.Fatal("Failed to ping database", zap.Error(err))
+    // Connect to database; the URL scheme selects the backend
+    db, driver, err := database.Open(cfg.Database.URL, cfg.Database.StatementTimeout)
+    if err != nil {
+        return fail("Failed to connect to database", err)
     }
+    lc.Register("database", func(context.Context) error {
+        return db.Close()
+    })
+    db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+    db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+    db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+    db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
     
-    logger.Log.Info("Successfully connected to database")
+    // Verify database connection, giving a slow-starting database time to
+    // come up; a signal cuts the wait short
+    startupCtx, stopStartup := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    err = database.WaitForDatabase(startupCtx, db, cfg.Database.StartupTimeout, cfg.Database.PingTimeout, logger.Log)
+    stopStartup()
+    if err != nil {
+        return fail("Failed to ping database", err)
+    }
+    
+    logger.Log.Info("Successfully connected to database", zap.String("driver", driver))
+    
+    if driver == database.DriverPostgres && cfg.Database.MigrateOnStart {
+        if err := database.MigratePostgres(context.Background(), db, migrations.FS); err != nil {
+            return fail("Failed to apply database migrations", err)
+        }
+        version, _, _ := database.CurrentVersion(context.Background(), db)
+        logger.Log.Info("Database migrations applied", zap.Int64("version", version))
+    }
+    
+    // Losing the database later degrades the service instead of stopping it
+    dbMonitor := database.NewMonitor(db, cfg.Database.HealthInterval, cfg.Database.PingTimeout, logger.Log)
+    lc.Go("database monitor", func(ctx context.Context) error {
+        dbMonitor.Run(ctx)
+        return nil
+    })
+    
+    healthRegistry := health.NewRegistry(cfg.Health.CacheTTL)
+    healthRegistry.Register("database", health.Readiness|health.Startup, cfg.Health.CheckTimeout, database.PingCheck(db))
+    healthRegistry.Register("database_monitor", health.Readiness, cfg.Health.CheckTimeout, dbMonitor.Check)
+    healthRegistry.Register("migrations", health.Readiness|health.Startup, cfg.Health.CheckTimeout, database.MigrationCheck(db, driver))
+    healthRegistry.Register("pool", health.Readiness, cfg.Health.CheckTimeout, database.PoolCheck(db, cfg.Health.PoolSaturation))
+    lc.BeforeStop(healthRegistry.Shutdown)
     
     // Initialize layers
-    userRepo := repository.NewUserRepository(db)
-    userService := service.NewUserService(userRepo, logger.Log)
+    broker := events.NewBroker(logger.Log)
+    
+    // SQLite is single node: events go straight to the broker and there are
+    // no webhooks or outbox
+    var userRepo repository.UserRepository
+    var webhookHandler *handler.WebhookHandler
+    if driver == database.DriverSQLite {
+        userRepo = repository.NewSQLiteUserRepository(db, broker)
+    } else {
+        userRepo = repository.NewUserRepository(db)
+        webhookHandler, err = startPostgresEvents(lc, cfg, db, broker)
+        if err != nil {
+            return fail("Failed to start event delivery", err)
+        }
+    }
+    
+    // USER_CACHE_SIZE=0 disables the cache
+    if cfg.Cache.Size > 0 {
+        var peers repository.CacheInvalidator
+        crossReplica := driver == database.DriverPostgres && cfg.Cache.Invalidation == "postgres"
+        if crossReplica {
+            peers = repository.NewNotifyInvalidator(pgnotify.NewNotifier(db))
+        }
+        cached := repository.NewCachedUserRepository(userRepo, repository.CacheConfig{
+            Size: cfg.Cache.Size,
+            TTL:  cfg.Cache.TTL,
+        }, peers, logger.Log)
+        if crossReplica {
+            lc.Go("user cache listener", func(ctx context.Context) error {
+                if err := repository.ListenForInvalidations(ctx, cfg.Database.URL, cached, logger.Log); err != nil {
+                    logger.Log.Error("User cache listener stopped", zap.Error(err))
+                }
+                return nil
+            })
+        }
+        userRepo = cached
+    }
+    
+    // Service operations spanning several repository calls run in one
+    // transaction, retried on serialization failures
+    isolation, err := database.ParseIsolation(cfg.Database.TxIsolation)
+    if err != nil {
+        return fail("Invalid transaction isolation level", err)
+    }
+    txManager := database.NewTxManager(db, driver, isolation, cfg.Database.TxMaxRetries)
+    
+    userService := service.NewUserService(userRepo, txManager, logger.Log)
     userHandler := handler.NewUserHandler(userService, logger.Log)
     
+    hub := sse.NewHub(cfg.Events.SSEReplayBuffer)
+    lc.Go("sse hub", func(ctx context.Context) error {
+        hub.Run(ctx, broker)
+        return nil
+    })
+    userEventsHandler := handler.NewUserEventsHandler(hub, cfg.Events.SSEHeartbeat, logger.Log)
+    
+    // gRPC server shares the same service instance
+    grpcServer := grpcapi.NewServer(grpcapi.NewUserServer(userService, broker, logger.Log), logger.Log, reporter)
+    grpcAddr := fmt.Sprintf(":%s", cfg.Server.GRPCPort)
+    grpcListener, err := net.Listen("tcp", grpcAddr)
+    if err != nil {
+        return fail("Failed to listen for gRPC", err)
+    }
+    logger.Log.Info("Starting gRPC server", zap.String("address", grpcAddr))
+    lc.Serve("grpc server", func() error {
+        return grpcServer.Serve(grpcListener)
+    }, func(ctx context.Context) error {
+        return grpcapi.Shutdown(ctx, grpcServer)
+    })
+    
+    clientResolver, err := clientip.NewResolver(cfg.Proxy.TrustedProxies, cfg.Proxy.Headers)
+    if err != nil {
+        return fail("Invalid proxy configuration", err)
+    }
+    authenticator := auth.NewAuthenticator(apiKeys(cfg.Auth.APIKeys), clientCerts(cfg.Auth.ClientCerts))
+    
+    // Operational endpoints get their own app and listener. Registered
+    // before the public server so it stops after it, keeping metrics up
+    // while requests drain.
+    if cfg.Admin.Address != "" {
+        // Profiles stream for their whole duration before the response ends
+        adminWriteTimeout := cfg.Server.WriteTimeout
+        if cfg.Admin.Diagnostics && adminWriteTimeout > 0 {
+            adminWriteTimeout += cfg.Admin.MaxProfileDuration
+        }
+        adminApp := fiber.New(fiber.Config{
+            ReadTimeout:  cfg.Server.ReadTimeout,
+            WriteTimeout: adminWriteTimeout,
+            IdleTimeout:  cfg.Server.IdleTimeout,
+            ErrorHandler: handler.ErrorHandler,
+            // The public app already prints fiber's banner
+            DisableStartupMessage: true,
+        })
+        adminApp.Use(middleware.RequestID())
+        adminApp.Use(middleware.RequestContext(logger.Log))
+        adminApp.Use(middleware.ClientIP(clientResolver))
+        adminApp.Use(middleware.Logger(accessLog, cfg.AccessLog.SkipPaths))
+        adminApp.Use(middleware.Recover(logger.Log, reporter))
+        adminApp.Use(middleware.Authenticate(authenticator))
+        routes.SetupAdminRoutes(adminApp, handler.NewHealthHandler(healthRegistry, true), handler.NewLogLevelHandler(), webhookHandler, cfg.Limits.RequestTimeout)
+        if cfg.Admin.Diagnostics {
+            // Anyone who can reach a loopback address or the socket is
+            // already on the host
+            var guards []fiber.Handler
+            if !isLocal(cfg.Admin.Address) {
+                guards = append(guards, middleware.RequireScope(auth.ScopeAdmin))
+            }
+            routes.SetupDiagnosticsRoutes(adminApp, handler.NewDiagnosticsHandler(), cfg.Admin.MaxProfileDuration, guards...)
+        }
+        
+        adminListener, err := listen(cfg.Admin.Address)
+        if err != nil {
+            return fail("Failed to start admin server", err)
+        }
+        logger.Log.Info("Starting admin server", zap.String("address", cfg.Admin.Address))
+        lc.Serve("admin server", func() error {
+            return adminApp.Listener(adminListener)
+        }, func(ctx context.Context) error {
+            deadline, _ := ctx.Deadline()
+            return adminApp.ShutdownWithTimeout(time.Until(deadline))
+        })
+    }
+    
     // Create Fiber app
     app := fiber.New(fiber.Config{
-        ErrorHandler: func(c *fiber.Ctx, err error) error {
-            code := fiber.StatusInternalServerError
-            if e, ok := err.(*fiber.Error); ok {
-                code = e.Code
-            }
-            return c.Status(code).JSON(fiber.Map{
-                "error": err.Error(),
-            })
-        },
+        BodyLimit:    cfg.Limits.BodyLimit,
+        ReadTimeout:  cfg.Server.ReadTimeout,
+        WriteTimeout: cfg.Server.WriteTimeout,
+        IdleTimeout:  cfg.Server.IdleTimeout,
+        // c.Protocol() only believes X-Forwarded-Proto from trusted proxies
+        EnableTrustedProxyCheck: true,
+        TrustedProxies:          cfg.Proxy.TrustedProxies,
+        ErrorHandler:            handler.ErrorHandler,
     })
     
     // Middleware
-    app.Use(cors.New())
+    corsPolicies := make([]middleware.CORSPolicy, 0, len(cfg.CORS.Groups))
+    for _, group := range cfg.CORS.Groups {
+        corsPolicies = append(corsPolicies, middleware.CORSPolicy{
+            Prefix: group.Prefix,
+            Config: corsConfig(cfg.CORS.Override(group)),
+        })
+    }
+    app.Use(middleware.CORS(corsConfig(cfg.CORS), corsPolicies))
+    app.Use(middleware.SecurityHeaders(helmet.Config{
+        XFrameOptions:         cfg.Security.FrameOptions,
+        ReferrerPolicy:        cfg.Security.ReferrerPolicy,
+        ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+        HSTSMaxAge:            int(cfg.Security.HSTSMaxAge.Seconds()),
+        HSTSExcludeSubdomains: !cfg.Security.HSTSIncludeSubdomains,
+        HSTSPreloadEnabled:    cfg.Security.HSTSPreload,
+    }, cfg.Security.DocsPath, cfg.Security.DocsContentSecurityPolicy))
     app.Use(middleware.RequestID())
-    app.Use(middleware.Logger(logger.Log))
-    app.Use(middleware.Recover(logger.Log))
+    app.Use(middleware.RequestContext(logger.Log))
+    app.Use(middleware.ClientIP(clientResolver))
+    // Recover sits inside Logger so a panicked request is logged with the
+    // 500 it actually got, and outside everything that could panic
+    app.Use(middleware.Logger(accessLog, cfg.AccessLog.SkipPaths))
+    app.Use(middleware.Recover(logger.Log, reporter))
+    app.Use(middleware.Authenticate(authenticator))
     
     // Setup routes
-    routes.SetupRoutes(app, userHandler)
-    
-    // Graceful shutdown
-    go func() {
-        sigChan := make(chan os.Signal, 1)
-        signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-        <-sigChan
-        
-        logger.Log.Info("Shutting down server...")
-        app.Shutdown()
-    }()
+    graphqlHandler := graph.NewHandler(userService, cfg.Limits.GraphQLMaxDepth, cfg.Limits.GraphQLMaxComplexity, logger.Log)
+    routes.SetupRoutes(app, userHandler, userEventsHandler, graphqlHandler, handler.NewHealthHandler(healthRegistry, false), handler.NewVersionHandler(), cfg.Limits.RequestTimeout)
     
     // Start server
-    addr := fmt.Sprintf(":%s", cfg.ServerPort)
-    logger.Log.Info("Starting server", zap.String("address", addr))
+    addr := fmt.Sprintf(":%s", cfg.Server.Port)
+    listener, err := net.Listen("tcp", addr)
+    if err != nil {
+        return fail("Failed to start server", err)
+    }
+    if cfg.Server.TLS.CertFile != "" {
+        reloader, err := certs.NewReloader(certs.Config{
+            CertFile:     cfg.Server.TLS.CertFile,
+            KeyFile:      cfg.Server.TLS.KeyFile,
+            MinVersion:   cfg.Server.TLS.MinVersion,
+            CipherSuites: cfg.Server.TLS.CipherSuites,
+            ClientCAFile: cfg.Server.TLS.ClientCAFile,
+            ClientAuth:   cfg.Server.TLS.ClientAuth,
+        }, logger.Log)
+        if err != nil {
+            listener.Close()
+            return fail("Failed to load TLS certificate", err)
+        }
+        lc.Go("tls certificate reloader", func(ctx context.Context) error {
+            return reloader.Watch(ctx, cfg.Server.TLS.ReloadInterval)
+        })
+        listener = tls.NewListener(listener, reloader.TLSConfig())
+    }
+    logger.Log.Info("Starting server", zap.String("address", addr), zap.Bool("tls", cfg.Server.TLS.CertFile != ""))
+    lc.Serve("http server", func() error {
+        return app.Listener(listener)
+    }, func(ctx context.Context) error {
+        // In-flight requests get whatever is left of the shutdown timeout
+        deadline, _ := ctx.Deadline()
+        return app.ShutdownWithTimeout(time.Until(deadline))
+    })
+    
+    // Registered after the HTTP server so it stops first: open SSE streams
+    // would otherwise hold the drain up until the deadline
+    lc.Register("sse streams", func(context.Context) error {
+        hub.Close()
+        return nil
+    })
+    
+    // Block until a signal or a failure, then shut down
+    return lc.Wait()
+}
+
+// listen opens a TCP listener for host:port, or a Unix socket for
+// unix:/path, replacing a socket left behind by an earlier run.
+func listen(address string) (net.Listener, error) {
+    path, ok := strings.CutPrefix(address, "unix:")
+    if !ok {
+        return net.Listen("tcp", address)
+    }
+    
+    // Only remove a stale socket, never a file that happens to be there
+    if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSocket != 0 {
+        if err := os.Remove(path); err != nil {
+            return nil, err
+        }
+    }
+    return net.Listen("unix", path)
+}
+
+// isLocal reports whether a listen address can only be reached from this
+// host.
+func isLocal(address string) bool {
+    if strings.HasPrefix(address, "unix:") {
+        return true
+    }
+    host, _, err := net.SplitHostPort(address)
+    if err != nil {
+        return false
+    }
+    if host == "localhost" {
+        return true
+    }
+    ip := net.ParseIP(host)
+    return ip != nil && ip.IsLoopback()
+}
+
+// startPostgresEvents starts the webhook dispatcher and outbox relay, and
+// returns the handler for the webhook admin API.
+func startPostgresEvents(lc *lifecycle.Manager, cfg *config.Config, db *sql.DB, broker *events.Broker) (*handler.WebhookHandler, error) {
+    webhookRepo := repository.NewWebhookRepository(db)
+    webhookService := service.NewWebhookService(webhookRepo, logger.Log)
+    
+    // With Postgres fan-out every replica's broker sees every event, not
+    // just the ones relayed locally
+    var fanout events.Publisher = broker
+    if cfg.Events.Fanout == "postgres" {
+        fanout = events.NewNotifyPublisher(pgnotify.NewNotifier(db))
+        lc.Go("user event listener", func(ctx context.Context) error {
+            if err := events.ListenAndPublish(ctx, cfg.Database.URL, broker, logger.Log); err != nil {
+                logger.Log.Error("User event listener stopped", zap.Error(err))
+            }
+            return nil
+        })
+    }
+    
+    dispatcher := webhook.NewDispatcher(webhookRepo, webhook.DefaultConfig(), logger.Log)
+    lc.Go("webhook dispatcher", func(ctx context.Context) error {
+        dispatcher.Run(ctx)
+        return nil
+    })
+    
+    // Committed user changes reach webhooks, the broker and through it gRPC
+    // watchers and SSE streams, only via the outbox relay
+    externalPublisher, err := outbox.NewPublisher(cfg.Events.OutboxPublisher, cfg.Events.OutboxTarget, cfg.Events.OutboxSubjectPrefix, logger.Log)
+    if err != nil {
+        return nil, err
+    }
+    relay := outbox.NewRelay(db, outbox.MultiPublisher{externalPublisher, dispatcher, fanout}, outbox.DefaultConfig(), logger.Log)
+    lc.Go("outbox relay", func(ctx context.Context) error {
+        relay.Run(ctx)
+        return nil
+    })
+    
+    return handler.NewWebhookHandler(webhookService, logger.Log), nil
+}
+
+// startErrorReporter builds the configured error report sinks and runs the
+// dispatcher that feeds them until shutdown.
+func startErrorReporter(lc *lifecycle.Manager, cfg config.ErrorReportingConfig) (*errreport.Dispatcher, error) {
+    var sinks []errreport.Sink
+    for _, name := range cfg.Sinks {
+        switch name {
+        case "log":
+            sinks = append(sinks, errreport.NewLogSink(logger.Log))
+        case "file":
+            sink, err := errreport.NewFileSink(cfg.File)
+            if err != nil {
+                return nil, err
+            }
+            lc.Register("error report file", func(context.Context) error {
+                return sink.Close()
+            })
+            sinks = append(sinks, sink)
+        case "http":
+            sinks = append(sinks, errreport.NewHTTPSink(cfg.URL, cfg.Timeout))
+        }
+    }
     
-    if err := app.Listen(addr); err != nil {
-        logger.Log.Fatal("Failed to start server", zap.Error(err))
+    reporter := errreport.NewDispatcher(sinks, cfg.DedupWindow, cfg.QueueSize, logger.Log)
+    lc.Go("error reporter", reporter.Run)
+    return reporter, nil
+}
+
+func corsConfig(policy config.CORSConfig) cors.Config {
+    return cors.Config{
+        AllowOrigins:     strings.Join(policy.AllowOrigins, ","),
+        AllowMethods:     strings.Join(policy.AllowMethods, ","),
+        AllowHeaders:     strings.Join(policy.AllowHeaders, ","),
+        ExposeHeaders:    strings.Join(policy.ExposeHeaders, ","),
+        AllowCredentials: policy.AllowCredentials,
+        MaxAge:           int(policy.MaxAge.Seconds()),
+    }
+}
+
+func apiKeys(configured []config.APIKeyConfig) []auth.APIKey {
+    keys := make([]auth.APIKey, 0, len(configured))
+    for _, k := range configured {
+        keys = append(keys, auth.APIKey{Name: k.Name, Key: k.Key, Scopes: k.Scopes})
+    }
+    return keys
+}
+
+func clientCerts(configured []config.ClientCertConfig) []auth.ClientCert {
+    clients := make([]auth.ClientCert, 0, len(configured))
+    for _, c := range configured {
+        clients = append(clients, auth.ClientCert{Subject: c.Subject, Scopes: c.Scopes})
     }
-}
\ No newline at end of file
+    return clients
+}
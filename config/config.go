@@ -1,39 +1,368 @@
 package config
 
 import (
-    "os"
-    "strconv"
-
-    "github.com/joho/godotenv"
+    "time"
 )
 
+// Config is loaded from defaults, then an optional YAML or TOML file, then
+// environment variables, then command-line flags, each overriding the last.
+// Every leaf can be set from the environment through its env tag and from a
+// flag named after its file path, for example --server.port.
 type Config struct {
-    ServerPort string
-    DatabaseURL string
+    Server         ServerConfig         `yaml:"server" toml:"server"`
+    Proxy          ProxyConfig          `yaml:"proxy" toml:"proxy"`
+    Admin          AdminConfig          `yaml:"admin" toml:"admin"`
+    Database       DatabaseConfig       `yaml:"database" toml:"database"`
+    Logging        LoggingConfig        `yaml:"logging" toml:"logging"`
+    AccessLog      AccessLogConfig      `yaml:"access_log" toml:"access_log"`
+    ErrorReporting ErrorReportingConfig `yaml:"error_reporting" toml:"error_reporting"`
+    CORS           CORSConfig           `yaml:"cors" toml:"cors"`
+    Security       SecurityConfig       `yaml:"security" toml:"security"`
+    Auth           AuthConfig           `yaml:"auth" toml:"auth"`
+    Limits         LimitsConfig         `yaml:"limits" toml:"limits"`
+    Events         EventsConfig         `yaml:"events" toml:"events"`
+    Cache          CacheConfig          `yaml:"cache" toml:"cache"`
+    Health         HealthConfig         `yaml:"health" toml:"health"`
 }
 
-func Load() *Config {
-    // Load .env file
-    _ = godotenv.Load()
+type ServerConfig struct {
+    Port        string        `yaml:"port" toml:"port" env:"SERVER_PORT" validate:"required,numeric"`
+    GRPCPort    string        `yaml:"grpc_port" toml:"grpc_port" env:"GRPC_PORT" validate:"required,numeric"`
+    ReadTimeout time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT" validate:"gte=0"`
+    // WriteTimeout bounds the whole response, so any value cuts the
+    // /users/events stream after that long; 0 leaves streams open and
+    // relies on the route timeouts for everything else
+    WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" validate:"gte=0"`
+    IdleTimeout  time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" validate:"gte=0"`
+    // PreStopDelay keeps serving after readiness fails so load balancers
+    // can stop routing here first
+    PreStopDelay    time.Duration `yaml:"pre_stop_delay" toml:"pre_stop_delay" env:"SERVER_PRE_STOP_DELAY" validate:"gte=0"`
+    ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" validate:"gt=0"`
+    TLS             TLSConfig     `yaml:"tls" toml:"tls"`
+}
 
-    return &Config{
-        ServerPort: getEnv("SERVER_PORT", "3000"),
-        DatabaseURL : getEnv("DATABASE_URL", ""),
-    }
+// TLSConfig serves HTTPS when CertFile is set. The files are reloaded when
+// they change or on SIGHUP.
+type TLSConfig struct {
+    CertFile   string `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+    KeyFile    string `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+    MinVersion string `yaml:"min_version" toml:"min_version" env:"TLS_MIN_VERSION" validate:"oneof=1.2 1.3"`
+    // CipherSuites are Go names such as
+    // TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 and apply to TLS 1.2 only
+    CipherSuites []string `yaml:"cipher_suites" toml:"cipher_suites" env:"TLS_CIPHER_SUITES"`
+    // ClientCAFile turns on mutual TLS; verified client certificates
+    // authenticate their common name, see auth.client_certs
+    ClientCAFile   string        `yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+    ClientAuth     string        `yaml:"client_auth" toml:"client_auth" env:"TLS_CLIENT_AUTH" validate:"oneof=none optional require"`
+    ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval" env:"TLS_RELOAD_INTERVAL" validate:"gte=0"`
+}
+
+// ProxyConfig says which peers may report the client address and scheme.
+type ProxyConfig struct {
+    // TrustedProxies are the IPs or CIDRs of load balancers in front of
+    // the server; forwarding headers from anyone else are ignored
+    TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES" validate:"dive,cidr|ip"`
+    // Headers are tried in order until one is present
+    Headers []string `yaml:"headers" toml:"headers" env:"PROXY_HEADERS" validate:"dive,oneof=forwarded x-forwarded-for x-real-ip"`
+}
+
+// AdminConfig puts operational endpoints such as metrics and log level
+// control on a listener of their own, never on the public port.
+type AdminConfig struct {
+    // Address is host:port, or unix:/path for a Unix socket; empty turns
+    // the operational endpoints off
+    Address string `yaml:"address" toml:"address" env:"ADMIN_ADDRESS"`
+    // Diagnostics serves pprof, expvar and goroutine dumps under /debug.
+    // Unless Address is loopback or a Unix socket they need the admin scope.
+    Diagnostics        bool          `yaml:"diagnostics" toml:"diagnostics" env:"ADMIN_DIAGNOSTICS"`
+    MaxProfileDuration time.Duration `yaml:"max_profile_duration" toml:"max_profile_duration" env:"ADMIN_MAX_PROFILE_DURATION" validate:"gt=0"`
+}
+
+type DatabaseConfig struct {
+    // URL selects the backend by scheme: postgres:// or sqlite://path
+    URL             string        `yaml:"url" toml:"url" env:"DATABASE_URL" secret:"true" validate:"required"`
+    MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" validate:"gte=0"`
+    MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" validate:"gte=0"`
+    ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" validate:"gte=0"`
+    ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" validate:"gte=0"`
+    PingTimeout     time.Duration `yaml:"ping_timeout" toml:"ping_timeout" env:"DB_PING_TIMEOUT" validate:"gt=0"`
+    StartupTimeout  time.Duration `yaml:"startup_timeout" toml:"startup_timeout" env:"DB_STARTUP_TIMEOUT" validate:"gte=0"`
+    // StatementTimeout is the server-side limit for any single Postgres
+    // statement; transactions tighten it to the request deadline
+    StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" validate:"gte=0"`
+    HealthInterval   time.Duration `yaml:"health_interval" toml:"health_interval" env:"DB_HEALTH_INTERVAL" validate:"gt=0"`
+    TxIsolation      string        `yaml:"tx_isolation" toml:"tx_isolation" env:"TX_ISOLATION" validate:"oneof=default read_uncommitted read_committed repeatable_read serializable"`
+    TxMaxRetries     int           `yaml:"tx_max_retries" toml:"tx_max_retries" env:"TX_MAX_RETRIES" validate:"gte=0"`
+    // MigrateOnStart applies the Postgres migrations built into the binary
+    // before serving. Left off, they must be applied from db/migrations
+    // before each upgrade; SQLite is always migrated
+    MigrateOnStart bool `yaml:"migrate_on_start" toml:"migrate_on_start" env:"DB_MIGRATE_ON_START"`
+}
+
+type LoggingConfig struct {
+    Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+    Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" validate:"oneof=json console"`
+    // OutputPaths are "stdout", "stderr" or file paths
+    OutputPaths     []string       `yaml:"output_paths" toml:"output_paths" env:"LOG_OUTPUT_PATHS" validate:"min=1"`
+    Sampling        SamplingConfig `yaml:"sampling" toml:"sampling"`
+    StacktraceLevel string         `yaml:"stacktrace_level" toml:"stacktrace_level" env:"LOG_STACKTRACE_LEVEL" validate:"omitempty,oneof=debug info warn error"`
+    // PackageLevels override level for single packages, as
+    // "internal/service=debug"
+    PackageLevels []string `yaml:"package_levels" toml:"package_levels" env:"LOG_PACKAGE_LEVELS"`
+}
+
+// SamplingConfig keeps the first Initial entries with the same level and
+// message each second, then every Thereafter-th. Thereafter 0 disables it.
+type SamplingConfig struct {
+    Initial    int `yaml:"initial" toml:"initial" env:"LOG_SAMPLING_INITIAL" validate:"gte=0"`
+    Thereafter int `yaml:"thereafter" toml:"thereafter" env:"LOG_SAMPLING_THEREAFTER" validate:"gte=0"`
+}
+
+// AccessLogConfig controls the per-request log, which is written apart from
+// the application log.
+type AccessLogConfig struct {
+    Format string `yaml:"format" toml:"format" env:"ACCESS_LOG_FORMAT" validate:"oneof=json logfmt combined"`
+    // Output is "stdout", "stderr" or a file path; files are rotated
+    Output string `yaml:"output" toml:"output" env:"ACCESS_LOG_OUTPUT" validate:"required"`
+    // Fields adds optional fields to json and logfmt lines
+    Fields    []string       `yaml:"fields" toml:"fields" env:"ACCESS_LOG_FIELDS" validate:"dive,oneof=user_agent referer bytes_in bytes_out route"`
+    SkipPaths []string       `yaml:"skip_paths" toml:"skip_paths" env:"ACCESS_LOG_SKIP_PATHS"`
+    Rotation  RotationConfig `yaml:"rotation" toml:"rotation"`
+    // CaptureBodies logs request and response bodies with personal data
+    // masked, up to MaxBodyBytes each
+    CaptureBodies bool `yaml:"capture_bodies" toml:"capture_bodies" env:"ACCESS_LOG_CAPTURE_BODIES"`
+    MaxBodyBytes  int  `yaml:"max_body_bytes" toml:"max_body_bytes" env:"ACCESS_LOG_MAX_BODY_BYTES" validate:"gt=0"`
+}
+
+// RotationConfig applies when the access log is written to a file. MaxAge
+// is rounded up to whole days; 0 keeps rotated files forever.
+type RotationConfig struct {
+    MaxSizeMB  int           `yaml:"max_size_mb" toml:"max_size_mb" env:"ACCESS_LOG_MAX_SIZE_MB" validate:"gt=0"`
+    MaxAge     time.Duration `yaml:"max_age" toml:"max_age" env:"ACCESS_LOG_MAX_AGE" validate:"gte=0"`
+    MaxBackups int           `yaml:"max_backups" toml:"max_backups" env:"ACCESS_LOG_MAX_BACKUPS" validate:"gte=0"`
+    Compress   bool          `yaml:"compress" toml:"compress" env:"ACCESS_LOG_COMPRESS"`
+}
+
+// ErrorReportingConfig selects where panics and 5xx responses are reported.
+// Repeats of one failure within DedupWindow are counted instead of sent.
+type ErrorReportingConfig struct {
+    // Sinks are any of "log", "file" and "http"
+    Sinks       []string      `yaml:"sinks" toml:"sinks" env:"ERROR_REPORT_SINKS" validate:"dive,oneof=log file http"`
+    File        string        `yaml:"file" toml:"file" env:"ERROR_REPORT_FILE"`
+    URL         string        `yaml:"url" toml:"url" env:"ERROR_REPORT_URL" secret:"true" validate:"omitempty,url"`
+    Timeout     time.Duration `yaml:"timeout" toml:"timeout" env:"ERROR_REPORT_TIMEOUT" validate:"gt=0"`
+    DedupWindow time.Duration `yaml:"dedup_window" toml:"dedup_window" env:"ERROR_REPORT_DEDUP_WINDOW" validate:"gte=0"`
+    QueueSize   int           `yaml:"queue_size" toml:"queue_size" env:"ERROR_REPORT_QUEUE_SIZE" validate:"gt=0"`
+}
+
+type CORSConfig struct {
+    AllowOrigins     []string      `yaml:"allow_origins" toml:"allow_origins" env:"CORS_ALLOW_ORIGINS" validate:"min=1"`
+    AllowMethods     []string      `yaml:"allow_methods" toml:"allow_methods" env:"CORS_ALLOW_METHODS" validate:"min=1"`
+    AllowHeaders     []string      `yaml:"allow_headers" toml:"allow_headers" env:"CORS_ALLOW_HEADERS"`
+    ExposeHeaders    []string      `yaml:"expose_headers" toml:"expose_headers" env:"CORS_EXPOSE_HEADERS"`
+    AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+    MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" validate:"gte=0"`
+    // Groups override the policy for paths under a prefix, the longest
+    // match winning; they can only be set from a config file
+    Groups []CORSGroupConfig `yaml:"groups" toml:"groups" validate:"dive"`
+}
+
+// CORSGroupConfig overrides the CORS policy under Prefix. Empty lists and a
+// zero MaxAge keep the top-level values; AllowCredentials applies as set.
+type CORSGroupConfig struct {
+    Prefix           string        `yaml:"prefix" toml:"prefix" validate:"required,startswith=/"`
+    AllowOrigins     []string      `yaml:"allow_origins" toml:"allow_origins"`
+    AllowMethods     []string      `yaml:"allow_methods" toml:"allow_methods"`
+    AllowHeaders     []string      `yaml:"allow_headers" toml:"allow_headers"`
+    ExposeHeaders    []string      `yaml:"expose_headers" toml:"expose_headers"`
+    AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials"`
+    MaxAge           time.Duration `yaml:"max_age" toml:"max_age" validate:"gte=0"`
 }
 
-func getEnv(key, defaultValue string) string {
-    if value := os.Getenv(key); value != "" {
-        return value
+// Override returns the policy for group, filled in from c.
+func (c CORSConfig) Override(group CORSGroupConfig) CORSConfig {
+    policy := c
+    policy.Groups = nil
+    if len(group.AllowOrigins) > 0 {
+        policy.AllowOrigins = group.AllowOrigins
+    }
+    if len(group.AllowMethods) > 0 {
+        policy.AllowMethods = group.AllowMethods
+    }
+    if len(group.AllowHeaders) > 0 {
+        policy.AllowHeaders = group.AllowHeaders
     }
-    return defaultValue
+    if len(group.ExposeHeaders) > 0 {
+        policy.ExposeHeaders = group.ExposeHeaders
+    }
+    if group.MaxAge > 0 {
+        policy.MaxAge = group.MaxAge
+    }
+    policy.AllowCredentials = group.AllowCredentials
+    return policy
+}
+
+// SecurityConfig sets the hardening headers sent with every response.
+type SecurityConfig struct {
+    // HSTSMaxAge is only sent over HTTPS; 0 disables it
+    HSTSMaxAge            time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE" validate:"gte=0"`
+    HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" toml:"hsts_include_subdomains" env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS"`
+    HSTSPreload           bool          `yaml:"hsts_preload" toml:"hsts_preload" env:"SECURITY_HSTS_PRELOAD"`
+    ContentSecurityPolicy string        `yaml:"content_security_policy" toml:"content_security_policy" env:"SECURITY_CSP"`
+    // DocsPath serves an HTML page, so it gets a policy that lets the page
+    // load its own scripts and styles
+    DocsPath                  string `yaml:"docs_path" toml:"docs_path" env:"SECURITY_DOCS_PATH" validate:"omitempty,startswith=/"`
+    DocsContentSecurityPolicy string `yaml:"docs_content_security_policy" toml:"docs_content_security_policy" env:"SECURITY_DOCS_CSP"`
+    ReferrerPolicy            string `yaml:"referrer_policy" toml:"referrer_policy" env:"SECURITY_REFERRER_POLICY"`
+    FrameOptions              string `yaml:"frame_options" toml:"frame_options" env:"SECURITY_FRAME_OPTIONS" validate:"oneof=DENY SAMEORIGIN"`
+}
+
+type AuthConfig struct {
+    // APIKeys and ClientCerts can only be set from a config file
+    APIKeys     []APIKeyConfig     `yaml:"api_keys" toml:"api_keys" validate:"dive"`
+    ClientCerts []ClientCertConfig `yaml:"client_certs" toml:"client_certs" validate:"dive"`
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-    if value := os.Getenv(key); value != "" {
-        if intVal, err := strconv.Atoi(value); err == nil {
-            return intVal
-        }
+type APIKeyConfig struct {
+    Name   string   `yaml:"name" toml:"name" validate:"required"`
+    Key    string   `yaml:"key" toml:"key" secret:"true" validate:"required,min=16"`
+    Scopes []string `yaml:"scopes" toml:"scopes"`
+}
+
+// ClientCertConfig grants scopes to mutual TLS clients whose certificate
+// has Subject as its common name.
+type ClientCertConfig struct {
+    Subject string   `yaml:"subject" toml:"subject" validate:"required"`
+    Scopes  []string `yaml:"scopes" toml:"scopes"`
+}
+
+type LimitsConfig struct {
+    BodyLimit            int           `yaml:"body_limit" toml:"body_limit" env:"BODY_LIMIT" validate:"gt=0"`
+    RequestTimeout       time.Duration `yaml:"request_timeout" toml:"request_timeout" env:"REQUEST_TIMEOUT" validate:"gte=0"`
+    GraphQLMaxDepth      int           `yaml:"graphql_max_depth" toml:"graphql_max_depth" env:"GRAPHQL_MAX_DEPTH" validate:"gte=0"`
+    GraphQLMaxComplexity int           `yaml:"graphql_max_complexity" toml:"graphql_max_complexity" env:"GRAPHQL_MAX_COMPLEXITY" validate:"gte=0"`
+}
+
+type EventsConfig struct {
+    OutboxPublisher     string        `yaml:"outbox_publisher" toml:"outbox_publisher" env:"OUTBOX_PUBLISHER" validate:"oneof=log file http nats"`
+    OutboxTarget        string        `yaml:"outbox_target" toml:"outbox_target" env:"OUTBOX_TARGET" secret:"true" validate:"required_unless=OutboxPublisher log"`
+    OutboxSubjectPrefix string        `yaml:"outbox_subject_prefix" toml:"outbox_subject_prefix" env:"OUTBOX_SUBJECT_PREFIX"`
+    Fanout              string        `yaml:"fanout" toml:"fanout" env:"EVENT_FANOUT" validate:"oneof=postgres local"`
+    SSEReplayBuffer     int           `yaml:"sse_replay_buffer" toml:"sse_replay_buffer" env:"SSE_REPLAY_BUFFER" validate:"gte=0"`
+    SSEHeartbeat        time.Duration `yaml:"sse_heartbeat" toml:"sse_heartbeat" env:"SSE_HEARTBEAT_SECONDS" validate:"gt=0"`
+}
+
+type CacheConfig struct {
+    // Size 0 disables the user cache
+    Size         int           `yaml:"size" toml:"size" env:"USER_CACHE_SIZE" validate:"gte=0"`
+    TTL          time.Duration `yaml:"ttl" toml:"ttl" env:"USER_CACHE_TTL_SECONDS" validate:"gte=0"`
+    Invalidation string        `yaml:"invalidation" toml:"invalidation" env:"USER_CACHE_INVALIDATION" validate:"oneof=postgres none"`
+}
+
+type HealthConfig struct {
+    // CacheTTL bounds how often probes actually run the checks
+    CacheTTL       time.Duration `yaml:"cache_ttl" toml:"cache_ttl" env:"HEALTH_CACHE_TTL" validate:"gte=0"`
+    CheckTimeout   time.Duration `yaml:"check_timeout" toml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"`
+    PoolSaturation float64       `yaml:"pool_saturation" toml:"pool_saturation" env:"HEALTH_POOL_SATURATION" validate:"gt=0,lte=1"`
+}
+
+func Default() *Config {
+    return &Config{
+        Server: ServerConfig{
+            Port:            "3000",
+            GRPCPort:        "9000",
+            ReadTimeout:     30 * time.Second,
+            WriteTimeout:    0,
+            IdleTimeout:     2 * time.Minute,
+            PreStopDelay:    5 * time.Second,
+            ShutdownTimeout: 30 * time.Second,
+            TLS: TLSConfig{
+                MinVersion:     "1.2",
+                ClientAuth:     "optional",
+                ReloadInterval: 10 * time.Second,
+            },
+        },
+        Proxy: ProxyConfig{
+            Headers: []string{"x-forwarded-for", "forwarded", "x-real-ip"},
+        },
+        Admin: AdminConfig{
+            Address:            "127.0.0.1:9090",
+            MaxProfileDuration: time.Minute,
+        },
+        Database: DatabaseConfig{
+            MaxOpenConns:     25,
+            MaxIdleConns:     25,
+            ConnMaxLifetime:  30 * time.Minute,
+            ConnMaxIdleTime:  5 * time.Minute,
+            PingTimeout:      5 * time.Second,
+            StartupTimeout:   time.Minute,
+            HealthInterval:   15 * time.Second,
+            StatementTimeout: 30 * time.Second,
+            TxIsolation:      "repeatable_read",
+            TxMaxRetries:     3,
+        },
+        Logging: LoggingConfig{
+            Level:       "info",
+            Format:      "json",
+            OutputPaths: []string{"stderr"},
+            Sampling: SamplingConfig{
+                Initial:    100,
+                Thereafter: 100,
+            },
+        },
+        AccessLog: AccessLogConfig{
+            Format:       "json",
+            Output:       "stdout",
+            Fields:       []string{"route", "bytes_out"},
+            SkipPaths:    []string{"/livez", "/readyz", "/startupz", "/health", "/metrics"},
+            MaxBodyBytes: 4096,
+            Rotation: RotationConfig{
+                MaxSizeMB:  100,
+                MaxAge:     7 * 24 * time.Hour,
+                MaxBackups: 10,
+                Compress:   true,
+            },
+        },
+        ErrorReporting: ErrorReportingConfig{
+            Timeout:     5 * time.Second,
+            DedupWindow: time.Minute,
+            QueueSize:   100,
+        },
+        CORS: CORSConfig{
+            AllowOrigins: []string{"*"},
+            AllowMethods: []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH"},
+        },
+        Security: SecurityConfig{
+            HSTSMaxAge:                365 * 24 * time.Hour,
+            HSTSIncludeSubdomains:     true,
+            ContentSecurityPolicy:     "default-src 'none'; frame-ancestors 'none'",
+            DocsPath:                  "/docs",
+            DocsContentSecurityPolicy: "default-src 'self'; img-src 'self' data:; style-src 'self' 'unsafe-inline'; frame-ancestors 'none'",
+            ReferrerPolicy:            "no-referrer",
+            FrameOptions:              "DENY",
+        },
+        Limits: LimitsConfig{
+            BodyLimit:            4 * 1024 * 1024,
+            RequestTimeout:       10 * time.Second,
+            GraphQLMaxDepth:      8,
+            GraphQLMaxComplexity: 1000,
+        },
+        Events: EventsConfig{
+            OutboxPublisher:     "log",
+            OutboxSubjectPrefix: "users",
+            Fanout:              "postgres",
+            SSEReplayBuffer:     1000,
+            SSEHeartbeat:        15 * time.Second,
+        },
+        Cache: CacheConfig{
+            Size:         10000,
+            TTL:          time.Minute,
+            Invalidation: "postgres",
+        },
+        Health: HealthConfig{
+            CacheTTL:       time.Second,
+            CheckTimeout:   2 * time.Second,
+            PoolSaturation: 0.9,
+        },
     }
-    return defaultValue
-}
\ No newline at end of file
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+    "bytes"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+    
+    "github.com/BurntSushi/toml"
+    "github.com/joho/godotenv"
+    "gopkg.in/yaml.v3"
+)
+
+// Options are command-line switches that are not part of Config.
+type Options struct {
+    ConfigFile  string
+    PrintConfig bool
+    Version     bool
+}
+
+// Error lists every problem found while loading, so all of them can be
+// fixed in one go.
+type Error struct {
+    Problems []string
+}
+
+func (e *Error) Error() string {
+    return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load builds the configuration from args (without the program name), the
+// file named by --config or CONFIG_FILE, and the environment, including a
+// .env file if present. It returns flag.ErrHelp for -h.
+func Load(args []string) (*Config, Options, error) {
+    cfg := Default()
+    var opts Options
+    
+    flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+    flags.StringVar(&opts.ConfigFile, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+    flags.BoolVar(&opts.PrintConfig, "print-config", false, "print the effective configuration with secrets redacted and exit")
+    flags.BoolVar(&opts.Version, "version", false, "print the version and exit")
+    overrides := registerFlags(flags, cfg)
+    if err := flags.Parse(args); err != nil {
+        return nil, opts, err
+    }
+    if flags.NArg() > 0 {
+        return nil, opts, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+    }
+    // Printing the version needs no valid configuration
+    if opts.Version {
+        return cfg, opts, nil
+    }
+    
+    _ = godotenv.Load()
+    
+    if opts.ConfigFile == "" {
+        opts.ConfigFile = os.Getenv("CONFIG_FILE")
+    }
+    if opts.ConfigFile != "" {
+        if err := loadFile(cfg, opts.ConfigFile); err != nil {
+            return nil, opts, err
+        }
+    }
+    
+    var problems []string
+    problems = append(problems, applyEnv(cfg)...)
+    problems = append(problems, overrides.apply()...)
+    problems = append(problems, cfg.validate()...)
+    if len(problems) > 0 {
+        return nil, opts, &Error{Problems: problems}
+    }
+    return cfg, opts, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+    contents, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("read config file: %w", err)
+    }
+    
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".yaml", ".yml":
+        decoder := yaml.NewDecoder(bytes.NewReader(contents))
+        decoder.KnownFields(true)
+        if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+            return fmt.Errorf("parse %s: %w", path, err)
+        }
+    case ".toml":
+        meta, err := toml.Decode(string(contents), cfg)
+        if err != nil {
+            return fmt.Errorf("parse %s: %w", path, err)
+        }
+        if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+            return fmt.Errorf("parse %s: unknown keys %v", path, undecoded)
+        }
+    default:
+        return fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+    }
+    return nil
+}
+
+// leaf is a settable scalar in the config tree, such as database.url.
+type leaf struct {
+    path   string
+    env    string
+    secret bool
+    value  reflect.Value
+}
+
+// leaves walks the tree in field order. Slices of structs, such as API keys,
+// are file-only and are not leaves.
+func leaves(v reflect.Value, prefix string) []leaf {
+    var result []leaf
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+        path := name
+        if prefix != "" {
+            path = prefix + "." + name
+        }
+        
+        value := v.Field(i)
+        switch {
+        case value.Kind() == reflect.Struct:
+            result = append(result, leaves(value, path)...)
+        case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct:
+            continue
+        default:
+            result = append(result, leaf{
+                path:   path,
+                env:    field.Tag.Get("env"),
+                secret: field.Tag.Get("secret") == "true",
+                value:  value,
+            })
+        }
+    }
+    return result
+}
+
+func applyEnv(cfg *Config) []string {
+    var problems []string
+    for _, l := range leaves(reflect.ValueOf(cfg).Elem(), "") {
+        if l.env == "" {
+            continue
+        }
+        raw, ok := os.LookupEnv(l.env)
+        if !ok || raw == "" {
+            continue
+        }
+        if err := setValue(l.value, raw); err != nil {
+            problems = append(problems, fmt.Sprintf("%s (env %s): %v", l.path, l.env, err))
+        }
+    }
+    return problems
+}
+
+// flagValue records a flag's raw value so it can be applied after the file
+// and environment, which are read once the flags are parsed.
+type flagValue struct {
+    leaf leaf
+    raw  *string
+}
+
+func (f *flagValue) String() string {
+    if f.leaf.value.IsValid() && !f.leaf.secret {
+        return formatValue(f.leaf.value)
+    }
+    return ""
+}
+
+func (f *flagValue) Set(raw string) error {
+    f.raw = &raw
+    return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+    return f.leaf.value.IsValid() && f.leaf.value.Kind() == reflect.Bool
+}
+
+type flagOverrides []*flagValue
+
+func registerFlags(flags *flag.FlagSet, cfg *Config) flagOverrides {
+    var overrides flagOverrides
+    for _, l := range leaves(reflect.ValueOf(cfg).Elem(), "") {
+        value := &flagValue{leaf: l}
+        usage := "set " + l.path
+        if l.env != "" {
+            usage += " (env " + l.env + ")"
+        }
+        flags.Var(value, l.path, usage)
+        overrides = append(overrides, value)
+    }
+    return overrides
+}
+
+func (o flagOverrides) apply() []string {
+    var problems []string
+    for _, f := range o {
+        if f.raw == nil {
+            continue
+        }
+        if err := setValue(f.leaf.value, *f.raw); err != nil {
+            problems = append(problems, fmt.Sprintf("%s (flag --%s): %v", f.leaf.path, f.leaf.path, err))
+        }
+    }
+    return problems
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses raw into v. Durations also accept a bare number of
+// seconds, which keeps older *_SECONDS variables working. Lists are comma
+// separated.
+func setValue(v reflect.Value, raw string) error {
+    raw = strings.TrimSpace(raw)
+    switch {
+    case v.Type() == durationType:
+        if seconds, err := strconv.Atoi(raw); err == nil {
+            v.SetInt(int64(time.Duration(seconds) * time.Second))
+            return nil
+        }
+        d, err := time.ParseDuration(raw)
+        if err != nil {
+            return fmt.Errorf("invalid duration %q", raw)
+        }
+        v.SetInt(int64(d))
+    case v.Kind() == reflect.String:
+        v.SetString(raw)
+    case v.Kind() == reflect.Int:
+        n, err := strconv.Atoi(raw)
+        if err != nil {
+            return fmt.Errorf("invalid integer %q", raw)
+        }
+        v.SetInt(int64(n))
+    case v.Kind() == reflect.Float64:
+        f, err := strconv.ParseFloat(raw, 64)
+        if err != nil {
+            return fmt.Errorf("invalid number %q", raw)
+        }
+        v.SetFloat(f)
+    case v.Kind() == reflect.Bool:
+        b, err := strconv.ParseBool(raw)
+        if err != nil {
+            return fmt.Errorf("invalid boolean %q", raw)
+        }
+        v.SetBool(b)
+    case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+        var items []string
+        for _, item := range strings.Split(raw, ",") {
+            if item = strings.TrimSpace(item); item != "" {
+                items = append(items, item)
+            }
+        }
+        v.Set(reflect.ValueOf(items))
+    default:
+        return fmt.Errorf("unsupported type %s", v.Type())
+    }
+    return nil
+}
+
+func formatValue(v reflect.Value) string {
+    switch {
+    case v.Type() == durationType:
+        return time.Duration(v.Int()).String()
+    case v.Kind() == reflect.Slice:
+        items := make([]string, v.Len())
+        for i := range items {
+            items[i] = fmt.Sprint(v.Index(i).Interface())
+        }
+        return strings.Join(items, ",")
+    default:
+        return fmt.Sprint(v.Interface())
+    }
+}
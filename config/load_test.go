@@ -0,0 +1,228 @@
+package config
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "slices"
+    "testing"
+    "time"
+)
+
+// setupLoad runs Load from an empty directory, so no stray .env file is
+// picked up, and writes files into it.
+func setupLoad(t *testing.T, files map[string]string) string {
+    t.Helper()
+    dir := t.TempDir()
+    t.Chdir(dir)
+    for name, contents := range files {
+        if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+            t.Fatal(err)
+        }
+    }
+    return dir
+}
+
+func TestLoadPrecedence(t *testing.T) {
+    const (
+        yamlFile = "server:\n  port: \"4000\"\n  read_timeout: 40s\ndatabase:\n  url: postgres://file\n"
+        tomlFile = "[server]\nport = \"4000\"\nread_timeout = \"40s\"\n\n[database]\nurl = \"postgres://file\"\n"
+    )
+    
+    tests := []struct {
+        name        string
+        files       map[string]string
+        env         map[string]string
+        args        []string
+        wantPort    string
+        wantTimeout time.Duration
+        wantURL     string
+    }{
+        {
+            name:        "defaults",
+            args:        []string{"--database.url", "postgres://flag"},
+            wantPort:    "3000",
+            wantTimeout: 30 * time.Second,
+            wantURL:     "postgres://flag",
+        },
+        {
+            name:        "YAML file over defaults",
+            files:       map[string]string{"config.yaml": yamlFile},
+            args:        []string{"--config", "config.yaml"},
+            wantPort:    "4000",
+            wantTimeout: 40 * time.Second,
+            wantURL:     "postgres://file",
+        },
+        {
+            name:        "TOML file over defaults",
+            files:       map[string]string{"config.toml": tomlFile},
+            args:        []string{"--config", "config.toml"},
+            wantPort:    "4000",
+            wantTimeout: 40 * time.Second,
+            wantURL:     "postgres://file",
+        },
+        {
+            name:        "file named by CONFIG_FILE",
+            files:       map[string]string{"config.yaml": yamlFile},
+            env:         map[string]string{"CONFIG_FILE": "config.yaml"},
+            wantPort:    "4000",
+            wantTimeout: 40 * time.Second,
+            wantURL:     "postgres://file",
+        },
+        {
+            name:        "env over file",
+            files:       map[string]string{"config.yaml": yamlFile},
+            env:         map[string]string{"SERVER_PORT": "5000", "SERVER_READ_TIMEOUT": "50"},
+            args:        []string{"--config", "config.yaml"},
+            wantPort:    "5000",
+            wantTimeout: 50 * time.Second,
+            wantURL:     "postgres://file",
+        },
+        {
+            name:        "env over .env file",
+            files:       map[string]string{".env": "SERVER_PORT=4500\nDATABASE_URL=postgres://dotenv\n"},
+            env:         map[string]string{"SERVER_PORT": "5000"},
+            wantPort:    "5000",
+            wantTimeout: 30 * time.Second,
+            wantURL:     "postgres://dotenv",
+        },
+        {
+            name:        "flags over env and file",
+            files:       map[string]string{"config.yaml": yamlFile},
+            env:         map[string]string{"SERVER_PORT": "5000", "DATABASE_URL": "postgres://env"},
+            args:        []string{"--config", "config.yaml", "--server.port", "6000", "--server.read_timeout=1m"},
+            wantPort:    "6000",
+            wantTimeout: time.Minute,
+            wantURL:     "postgres://env",
+        },
+        {
+            name:        "empty env is ignored",
+            files:       map[string]string{"config.yaml": yamlFile},
+            env:         map[string]string{"SERVER_PORT": ""},
+            args:        []string{"--config", "config.yaml"},
+            wantPort:    "4000",
+            wantTimeout: 40 * time.Second,
+            wantURL:     "postgres://file",
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            setupLoad(t, tt.files)
+            for _, key := range []string{"CONFIG_FILE", "SERVER_PORT", "SERVER_READ_TIMEOUT", "DATABASE_URL"} {
+                t.Setenv(key, tt.env[key])
+            }
+            // t.Setenv cannot unset; an empty value is skipped by Load,
+            // but godotenv only fills variables that are absent
+            for _, key := range []string{"SERVER_PORT", "DATABASE_URL"} {
+                if _, ok := tt.env[key]; !ok {
+                    os.Unsetenv(key)
+                }
+            }
+            
+            cfg, _, err := Load(tt.args)
+            if err != nil {
+                t.Fatalf("Load: %v", err)
+            }
+            if cfg.Server.Port != tt.wantPort {
+                t.Errorf("server.port = %q, want %q", cfg.Server.Port, tt.wantPort)
+            }
+            if cfg.Server.ReadTimeout != tt.wantTimeout {
+                t.Errorf("server.read_timeout = %v, want %v", cfg.Server.ReadTimeout, tt.wantTimeout)
+            }
+            if cfg.Database.URL != tt.wantURL {
+                t.Errorf("database.url = %q, want %q", cfg.Database.URL, tt.wantURL)
+            }
+        })
+    }
+}
+
+func TestLoadAggregatesProblems(t *testing.T) {
+    tests := []struct {
+        name  string
+        files map[string]string
+        env   map[string]string
+        args  []string
+        want  []string
+    }{
+        {
+            name: "missing required value",
+            want: []string{"database.url (env DATABASE_URL): is required"},
+        },
+        {
+            name:  "problems from every source",
+            files: map[string]string{"config.yaml": "logging:\n  level: loud\ndatabase:\n  url: postgres://file\n  max_open_conns: 5\n  max_idle_conns: 10\n"},
+            env:   map[string]string{"SERVER_READ_TIMEOUT": "soon"},
+            args:  []string{"--config", "config.yaml", "--server.port", "http", "--cache.size=many"},
+            want: []string{
+                `server.read_timeout (env SERVER_READ_TIMEOUT): invalid duration "soon"`,
+                `cache.size (flag --cache.size): invalid integer "many"`,
+                "server.port (env SERVER_PORT): must be a number",
+                "logging.level (env LOG_LEVEL): must be one of debug, info, warn, error",
+                "database.max_idle_conns: must not exceed database.max_open_conns",
+            },
+        },
+        {
+            name: "cross-field checks",
+            args: []string{
+                "--database.url", "postgres://flag",
+                "--server.tls.cert_file", "cert.pem",
+                "--cors.allow_origins", "*",
+                "--cors.allow_credentials",
+                "--logging.package_levels", "internal/service",
+            },
+            want: []string{
+                "server.tls: cert_file and key_file must be set together",
+                `cors.allow_credentials: cannot be used with the wildcard origin "*"`,
+                `logging.package_levels: "internal/service" must be package=level with level debug, info, warn or error`,
+            },
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            setupLoad(t, tt.files)
+            for _, key := range []string{"SERVER_READ_TIMEOUT", "DATABASE_URL", "SERVER_PORT", "LOG_LEVEL"} {
+                t.Setenv(key, tt.env[key])
+            }
+            
+            _, _, err := Load(tt.args)
+            var configErr *Error
+            if !errors.As(err, &configErr) {
+                t.Fatalf("err = %v, want *Error", err)
+            }
+            if !slices.Equal(configErr.Problems, tt.want) {
+                t.Errorf("problems:\n%q\nwant:\n%q", configErr.Problems, tt.want)
+            }
+        })
+    }
+}
+
+func TestLoadFileErrors(t *testing.T) {
+    tests := []struct {
+        name  string
+        files map[string]string
+        file  string
+    }{
+        {"missing file", nil, "absent.yaml"},
+        {"unknown YAML key", map[string]string{"config.yaml": "server:\n  prot: \"1\"\n"}, "config.yaml"},
+        {"unknown TOML key", map[string]string{"config.toml": "[server]\nprot = \"1\"\n"}, "config.toml"},
+        {"unsupported extension", map[string]string{"config.json": "{}"}, "config.json"},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            setupLoad(t, tt.files)
+            t.Setenv("DATABASE_URL", "postgres://env")
+            
+            _, _, err := Load([]string{"--config", tt.file})
+            if err == nil {
+                t.Fatal("Load succeeded")
+            }
+            var configErr *Error
+            if errors.As(err, &configErr) {
+                t.Errorf("file errors stop loading before validation, got %v", err)
+            }
+        })
+    }
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+    "fmt"
+    "io"
+    "net/url"
+    "reflect"
+    "strings"
+    "time"
+    
+    "gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+// Print writes the configuration as YAML, in the layout of a config file,
+// with secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+    encoder := yaml.NewEncoder(w)
+    encoder.SetIndent(2)
+    if err := encoder.Encode(toNode(reflect.ValueOf(c).Elem(), false)); err != nil {
+        return err
+    }
+    return encoder.Close()
+}
+
+func toNode(v reflect.Value, secret bool) *yaml.Node {
+    switch {
+    case v.Type() == durationType:
+        return scalar("!!str", time.Duration(v.Int()).String())
+    case v.Kind() == reflect.Struct:
+        node := &yaml.Node{Kind: yaml.MappingNode}
+        t := v.Type()
+        for i := 0; i < t.NumField(); i++ {
+            field := t.Field(i)
+            name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+            node.Content = append(node.Content,
+                scalar("!!str", name),
+                toNode(v.Field(i), field.Tag.Get("secret") == "true"),
+            )
+        }
+        return node
+    case v.Kind() == reflect.Slice:
+        node := &yaml.Node{Kind: yaml.SequenceNode}
+        if v.Len() == 0 || v.Type().Elem().Kind() != reflect.Struct {
+            node.Style = yaml.FlowStyle
+        }
+        for i := 0; i < v.Len(); i++ {
+            node.Content = append(node.Content, toNode(v.Index(i), secret))
+        }
+        return node
+    case v.Kind() == reflect.String:
+        value := v.String()
+        if secret && value != "" {
+            value = redact(value)
+        }
+        return scalar("!!str", value)
+    case v.Kind() == reflect.Bool:
+        return scalar("!!bool", fmt.Sprint(v.Bool()))
+    case v.Kind() == reflect.Float64:
+        return scalar("!!float", fmt.Sprint(v.Float()))
+    default:
+        return scalar("!!int", fmt.Sprint(v.Interface()))
+    }
+}
+
+func scalar(tag, value string) *yaml.Node {
+    return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// redact keeps the scheme and host of URLs, such as the database host, and
+// hides everything else: credentials, which can also sit in the user name
+// as in a Sentry DSN, the path and the query values.
+func redact(value string) string {
+    u, err := url.Parse(value)
+    if err != nil || u.Scheme == "" || u.Opaque != "" {
+        return redacted
+    }
+    
+    if u.User != nil {
+        u.User = url.User(redacted)
+    }
+    if u.Path != "" && u.Path != "/" {
+        u.Path, u.RawPath = "/"+redacted, ""
+    }
+    query := u.Query()
+    for key := range query {
+        query.Set(key, redacted)
+    }
+    u.RawQuery = query.Encode()
+    u.Fragment, u.RawFragment = "", ""
+    return u.String()
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+    "fmt"
+    "reflect"
+    "slices"
+    "strings"
+    
+    "github.com/go-playground/validator/v10"
+)
+
+func (c *Config) validate() []string {
+    validate := validator.New()
+    // Report fields by their file path, e.g. database.url
+    validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+        name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+        return name
+    })
+    
+    envs := make(map[string]string)
+    for _, l := range leaves(reflect.ValueOf(c).Elem(), "") {
+        envs[l.path] = l.env
+    }
+    
+    var problems []string
+    if err := validate.Struct(c); err != nil {
+        validationErrors, ok := err.(validator.ValidationErrors)
+        if !ok {
+            return []string{err.Error()}
+        }
+        for _, fieldError := range validationErrors {
+            // Drop the root type name from Config.database.url
+            _, path, _ := strings.Cut(fieldError.Namespace(), ".")
+            if env := envs[path]; env != "" {
+                path += " (env " + env + ")"
+            }
+            problems = append(problems, path+": "+describe(fieldError))
+        }
+    }
+    
+    if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+        problems = append(problems, "database.max_idle_conns: must not exceed database.max_open_conns")
+    }
+    if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+        problems = append(problems, "server.tls: cert_file and key_file must be set together")
+    }
+    if c.Server.TLS.ClientAuth == "require" && c.Server.TLS.ClientCAFile == "" {
+        problems = append(problems, "server.tls.client_ca_file: is required when client_auth is require")
+    }
+    if c.Server.TLS.ClientCAFile != "" && c.Server.TLS.CertFile == "" {
+        problems = append(problems, "server.tls.client_ca_file: needs server.tls.cert_file")
+    }
+    if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowOrigins, "*") {
+        problems = append(problems, "cors.allow_credentials: cannot be used with the wildcard origin \"*\"")
+    }
+    for _, group := range c.CORS.Groups {
+        policy := c.CORS.Override(group)
+        if policy.AllowCredentials && slices.Contains(policy.AllowOrigins, "*") {
+            problems = append(problems, fmt.Sprintf("cors.groups: %s cannot allow credentials with the wildcard origin \"*\"", group.Prefix))
+        }
+    }
+    
+    for _, entry := range c.Logging.PackageLevels {
+        pkg, level, ok := strings.Cut(entry, "=")
+        if !ok || strings.TrimSpace(pkg) == "" || !slices.Contains([]string{"debug", "info", "warn", "error"}, strings.TrimSpace(level)) {
+            problems = append(problems, fmt.Sprintf("logging.package_levels: %q must be package=level with level debug, info, warn or error", entry))
+        }
+    }
+    
+    if slices.Contains(c.ErrorReporting.Sinks, "file") && c.ErrorReporting.File == "" {
+        problems = append(problems, "error_reporting.file: is required for the file sink")
+    }
+    if slices.Contains(c.ErrorReporting.Sinks, "http") && c.ErrorReporting.URL == "" {
+        problems = append(problems, "error_reporting.url: is required for the http sink")
+    }
+    
+    names := make(map[string]bool)
+    for _, key := range c.Auth.APIKeys {
+        if names[key.Name] {
+            problems = append(problems, fmt.Sprintf("auth.api_keys: duplicate name %q", key.Name))
+        }
+        names[key.Name] = true
+    }
+    return problems
+}
+
+func describe(fieldError validator.FieldError) string {
+    param := fieldError.Param()
+    switch fieldError.Tag() {
+    case "required":
+        return "is required"
+    case "required_unless":
+        field, value, _ := strings.Cut(param, " ")
+        return fmt.Sprintf("is required unless %s is %s", field, value)
+    case "numeric":
+        return "must be a number"
+    case "startswith":
+        return "must start with " + param
+    case "url":
+        return "must be a URL"
+    case "cidr|ip":
+        return "must be an IP address or CIDR"
+    case "oneof":
+        return "must be one of " + strings.ReplaceAll(param, " ", ", ")
+    case "gt":
+        return "must be greater than " + param
+    case "gte":
+        return "must be at least " + param
+    case "lte":
+        return "must be at most " + param
+    case "min":
+        if fieldError.Kind() == reflect.Slice {
+            return "must have at least " + param + " entries"
+        }
+        return "must be at least " + param + " characters"
+    default:
+        return fmt.Sprintf("failed %s validation", fieldError.Tag())
+    }
+}
@@ -0,0 +1,8 @@
+ALTER TABLE outbox
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN dead_at TIMESTAMPTZ;
+
+-- Dead events no longer hold back later events for the same user
+DROP INDEX outbox_unpublished_idx;
+CREATE INDEX outbox_pending_idx ON outbox (aggregate_id, id)
+WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,8 @@
+-- The outbox event a delivery was created for. Publishing an event again,
+-- after a relay retry or a partial failure, then leaves the deliveries it
+-- already has alone instead of adding duplicates. Older rows keep a NULL
+-- event_id, which the unique index does not compare.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN event_id BIGINT;
+
+CREATE UNIQUE INDEX webhook_deliveries_event_idx ON webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,12 @@
+package migrations
+
+import (
+    "embed"
+)
+
+// FS holds the Postgres migrations. They are applied outside the service
+// unless database.migrate_on_start is set; either way the binary checks
+// that the database has caught up with them.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,46 @@
+-- name: InsertOutboxEvent :one
+INSERT INTO outbox (aggregate_id, event_type, payload)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: ClaimOutboxEvents :many
+-- Only the oldest pending event of each user is eligible, so events for the
+-- same user are published in order even with several relays running. Rows
+-- are leased by pushing next_attempt_at forward, so no lock is held while
+-- they are published.
+UPDATE outbox
+SET next_attempt_at = NOW() + sqlc.arg(lease_seconds)::int * INTERVAL '1 second'
+WHERE id IN (
+    SELECT o.id FROM outbox o
+    WHERE o.published_at IS NULL
+      AND o.dead_at IS NULL
+      AND o.next_attempt_at <= NOW()
+      AND NOT EXISTS (
+          SELECT 1 FROM outbox p
+          WHERE p.aggregate_id = o.aggregate_id
+            AND p.published_at IS NULL
+            AND p.dead_at IS NULL
+            AND p.id < o.id
+      )
+    ORDER BY o.id
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox
+SET published_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: RecordOutboxFailure :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3,
+    dead_at = CASE WHEN sqlc.arg(dead)::bool THEN NOW() END
+WHERE id = $1;
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox
+WHERE published_at IS NOT NULL AND published_at < sqlc.arg(before)::timestamptz;
@@ -14,7 +14,7 @@ LIMIT $1 OFFSET $2;
 
 -- name: UpdateUser :one
 UPDATE users
-SET name = $2, dob = $3, updated_at = CURRENT_TIMESTAMP
+SET name = $2, dob = $3
 WHERE id = $1
 RETURNING *;
 
@@ -23,4 +23,18 @@ DELETE FROM users
 WHERE id = $1;
 
 -- name: CountUsers :one
-SELECT COUNT(*) FROM users;
\ No newline at end of file
+SELECT COUNT(*) FROM users;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE id > sqlc.arg(after_id)
+  AND (sqlc.narg(name_contains)::text IS NULL OR name ILIKE '%' || sqlc.narg(name_contains) || '%')
+  AND (sqlc.narg(born_after)::date IS NULL OR dob >= sqlc.narg(born_after))
+  AND (sqlc.narg(born_before)::date IS NULL OR dob <= sqlc.narg(born_before))
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
\ No newline at end of file
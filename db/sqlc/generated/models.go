@@ -5,11 +5,50 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type Outbox struct {
+	ID            int64
+	AggregateID   int32
+	EventType     string
+	Payload       json.RawMessage
+	Attempts      int32
+	LastError     sql.NullString
+	CreatedAt     time.Time
+	PublishedAt   sql.NullTime
+	NextAttemptAt time.Time
+	DeadAt        sql.NullTime
+}
+
 type User struct {
 	ID   int32
 	Name string
 	Dob  time.Time
 }
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int32
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+	EventID        sql.NullInt64
+}
+
+type WebhookSubscription struct {
+	ID         int32
+	Url        string
+	EventTypes []string
+	Secret     string
+	Active     bool
+	CreatedAt  time.Time
+}
@@ -7,7 +7,10 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const countUsers = `-- name: CountUsers :one
@@ -61,6 +64,35 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 	return i, err
 }
 
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, name, dob FROM users
+WHERE id = ANY($1::int[])
+ORDER BY id
+`
+
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []int32) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(&i.ID, &i.Name, &i.Dob); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
 SELECT id, name, dob FROM users
 ORDER BY id
@@ -95,9 +127,56 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, dob FROM users
+WHERE id > $1
+  AND ($2::text IS NULL OR name ILIKE '%' || $2 || '%')
+  AND ($3::date IS NULL OR dob >= $3)
+  AND ($4::date IS NULL OR dob <= $4)
+ORDER BY id
+LIMIT $5
+`
+
+type SearchUsersParams struct {
+	AfterID      int32
+	NameContains sql.NullString
+	BornAfter    sql.NullTime
+	BornBefore   sql.NullTime
+	RowLimit     int32
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.AfterID,
+		arg.NameContains,
+		arg.BornAfter,
+		arg.BornBefore,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(&i.ID, &i.Name, &i.Dob); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET name = $2, dob = $3, updated_at = CURRENT_TIMESTAMP
+SET name = $2, dob = $3
 WHERE id = $1
 RETURNING id, name, dob
 `
@@ -0,0 +1,40 @@
+-- name: CreateUser :one
+INSERT INTO users (name, dob)
+VALUES (?, ?)
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = ?;
+
+-- name: ListUsers :many
+SELECT * FROM users
+ORDER BY id
+LIMIT ? OFFSET ?;
+
+-- name: UpdateUser :one
+UPDATE users
+SET name = ?, dob = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE id IN (sqlc.slice(ids))
+ORDER BY id;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE id > sqlc.arg(after_id)
+  AND (CAST(sqlc.narg(name_contains) AS TEXT) IS NULL OR name LIKE '%' || sqlc.narg(name_contains) || '%')
+  AND (CAST(sqlc.narg(born_after) AS DATE) IS NULL OR dob >= sqlc.narg(born_after))
+  AND (CAST(sqlc.narg(born_before) AS DATE) IS NULL OR dob <= sqlc.narg(born_before))
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,10 @@
+package sqlite
+
+import (
+    "embed"
+)
+
+// Migrations are applied automatically when a sqlite:// database is opened.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,315 @@
+package accesslog
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+    
+    "gopkg.in/natefinch/lumberjack.v2"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+)
+
+const (
+    FormatJSON     = "json"
+    FormatLogfmt   = "logfmt"
+    FormatCombined = "combined"
+)
+
+// Optional fields, added to JSON and logfmt lines when enabled. The combined
+// format has a fixed layout and always includes referer, user agent and
+// response size.
+const (
+    FieldUserAgent = "user_agent"
+    FieldReferer   = "referer"
+    FieldBytesIn   = "bytes_in"
+    FieldBytesOut  = "bytes_out"
+    FieldRoute     = "route"
+)
+
+type Config struct {
+    Format string
+    // Output is "stdout", "stderr" or a file path. Files are rotated once
+    // they reach MaxSizeMB; rotated files older than MaxAge or beyond
+    // MaxBackups are removed
+    Output     string
+    Fields     []string
+    MaxSizeMB  int
+    MaxAge     time.Duration
+    MaxBackups int
+    Compress   bool
+    // CaptureBodies adds request and response bodies to JSON and logfmt
+    // lines, redacted and cut to MaxBodyBytes
+    CaptureBodies bool
+    MaxBodyBytes  int
+}
+
+// Entry describes one completed request. BytesOut is -1 for streamed
+// responses, whose size is not known when the line is written.
+type Entry struct {
+    Time      time.Time
+    Level     string
+    RemoteIP  string
+    Method    string
+    URI       string
+    Protocol  string
+    Route     string
+    Status    int
+    Duration  time.Duration
+    BytesIn   int
+    BytesOut  int
+    Referer   string
+    UserAgent string
+    RequestID string
+    TraceID   string
+    Principal string
+    
+    // Bodies are only read when capture is enabled
+    RequestContentType  string
+    RequestBody         []byte
+    ResponseContentType string
+    ResponseBody        []byte
+}
+
+// Logger writes access log lines, one per Write of the underlying output.
+type Logger struct {
+    format string
+    fields map[string]bool
+    // bodyLimit is the captured size per body, or -1 when capture is off
+    bodyLimit int
+    
+    mu  sync.Mutex
+    out io.Writer
+    // file is set when out is a rotated log file
+    file io.Closer
+}
+
+func New(cfg Config) (*Logger, error) {
+    l := &Logger{
+        format:    cfg.Format,
+        fields:    make(map[string]bool),
+        bodyLimit: -1,
+    }
+    if cfg.CaptureBodies {
+        l.bodyLimit = cfg.MaxBodyBytes
+    }
+    for _, field := range cfg.Fields {
+        l.fields[field] = true
+    }
+    
+    switch cfg.Format {
+    case FormatJSON, FormatLogfmt, FormatCombined:
+    default:
+        return nil, fmt.Errorf("unknown access log format %q", cfg.Format)
+    }
+    
+    switch cfg.Output {
+    case "stdout":
+        l.out = os.Stdout
+    case "stderr":
+        l.out = os.Stderr
+    case "":
+        return nil, fmt.Errorf("access log output is required")
+    default:
+        file := &lumberjack.Logger{
+            Filename:   cfg.Output,
+            MaxSize:    cfg.MaxSizeMB,
+            MaxAge:     days(cfg.MaxAge),
+            MaxBackups: cfg.MaxBackups,
+            Compress:   cfg.Compress,
+        }
+        l.out, l.file = file, file
+    }
+    return l, nil
+}
+
+// days rounds up, since lumberjack counts retention in whole days and 0
+// means keep forever.
+func days(d time.Duration) int {
+    if d <= 0 {
+        return 0
+    }
+    return int((d + 24*time.Hour - 1) / (24 * time.Hour))
+}
+
+func (l *Logger) Log(e Entry) {
+    var line []byte
+    switch l.format {
+    case FormatCombined:
+        line = l.combined(e)
+    case FormatLogfmt:
+        line = l.logfmt(e)
+    default:
+        line = l.json(e)
+    }
+    
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    _, _ = l.out.Write(line)
+}
+
+// Close closes the log file, if the output is one.
+func (l *Logger) Close() error {
+    if l.file == nil {
+        return nil
+    }
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.file.Close()
+}
+
+// combined follows the Apache combined log format, with the principal as
+// the remote user.
+func (l *Logger) combined(e Entry) []byte {
+    bytesOut := "-"
+    if e.BytesOut > 0 {
+        bytesOut = strconv.Itoa(e.BytesOut)
+    }
+    return fmt.Appendf(nil, "%s - %s [%s] \"%s %s %s\" %d %s %s %s\n",
+        orDash(e.RemoteIP),
+        orDash(e.Principal),
+        e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+        e.Method, e.URI, e.Protocol,
+        e.Status,
+        bytesOut,
+        strconv.Quote(orDash(e.Referer)),
+        strconv.Quote(orDash(e.UserAgent)),
+    )
+}
+
+func (l *Logger) logfmt(e Entry) []byte {
+    var b strings.Builder
+    pair := func(key, value string) {
+        if b.Len() > 0 {
+            b.WriteByte(' ')
+        }
+        b.WriteString(key)
+        b.WriteByte('=')
+        if value == "" || strings.ContainsAny(value, " =\"\\") || strings.ContainsFunc(value, isControl) {
+            value = strconv.Quote(value)
+        }
+        b.WriteString(value)
+    }
+    
+    pair("time", e.Time.Format(time.RFC3339Nano))
+    pair("level", e.Level)
+    pair("method", e.Method)
+    pair("uri", e.URI)
+    pair("status", strconv.Itoa(e.Status))
+    pair("duration", e.Duration.String())
+    pair("ip", e.RemoteIP)
+    pair("request_id", e.RequestID)
+    if e.TraceID != "" {
+        pair("trace_id", e.TraceID)
+    }
+    if e.Principal != "" {
+        pair("principal", e.Principal)
+    }
+    if l.fields[FieldRoute] {
+        pair("route", e.Route)
+    }
+    if l.fields[FieldBytesIn] {
+        pair("bytes_in", strconv.Itoa(e.BytesIn))
+    }
+    if l.fields[FieldBytesOut] {
+        pair("bytes_out", strconv.Itoa(e.BytesOut))
+    }
+    if l.fields[FieldReferer] {
+        pair("referer", e.Referer)
+    }
+    if l.fields[FieldUserAgent] {
+        pair("user_agent", e.UserAgent)
+    }
+    if requestBody, responseBody := l.bodies(e); requestBody != "" || responseBody != "" {
+        pair("request_body", requestBody)
+        pair("response_body", responseBody)
+    }
+    b.WriteByte('\n')
+    return []byte(b.String())
+}
+
+func isControl(r rune) bool {
+    return r < ' ' || r == 0x7f
+}
+
+type jsonLine struct {
+    Time      string  `json:"time"`
+    Level     string  `json:"level"`
+    Method    string  `json:"method"`
+    URI       string  `json:"uri"`
+    Status    int     `json:"status"`
+    Duration  float64 `json:"duration"`
+    IP        string  `json:"ip"`
+    RequestID string  `json:"request_id"`
+    TraceID   string  `json:"trace_id,omitempty"`
+    Principal string  `json:"principal,omitempty"`
+    Route     *string `json:"route,omitempty"`
+    BytesIn   *int    `json:"bytes_in,omitempty"`
+    BytesOut  *int    `json:"bytes_out,omitempty"`
+    Referer   *string `json:"referer,omitempty"`
+    UserAgent *string `json:"user_agent,omitempty"`
+    
+    RequestBody  string `json:"request_body,omitempty"`
+    ResponseBody string `json:"response_body,omitempty"`
+}
+
+// json matches the application log: durations are in seconds.
+func (l *Logger) json(e Entry) []byte {
+    line := jsonLine{
+        Time:      e.Time.Format(time.RFC3339Nano),
+        Level:     e.Level,
+        Method:    e.Method,
+        URI:       e.URI,
+        Status:    e.Status,
+        Duration:  e.Duration.Seconds(),
+        IP:        e.RemoteIP,
+        RequestID: e.RequestID,
+        TraceID:   e.TraceID,
+        Principal: e.Principal,
+    }
+    if l.fields[FieldRoute] {
+        line.Route = &e.Route
+    }
+    if l.fields[FieldBytesIn] {
+        line.BytesIn = &e.BytesIn
+    }
+    if l.fields[FieldBytesOut] {
+        line.BytesOut = &e.BytesOut
+    }
+    if l.fields[FieldReferer] {
+        line.Referer = &e.Referer
+    }
+    if l.fields[FieldUserAgent] {
+        line.UserAgent = &e.UserAgent
+    }
+    line.RequestBody, line.ResponseBody = l.bodies(e)
+    
+    data, err := json.Marshal(line)
+    if err != nil {
+        // Only strings and numbers, so this cannot happen
+        return nil
+    }
+    return append(data, '\n')
+}
+
+// bodies applies the redaction policy to captured bodies, which hold
+// whatever personal data clients sent or were sent.
+func (l *Logger) bodies(e Entry) (request, response string) {
+    if l.bodyLimit < 0 {
+        return "", ""
+    }
+    return redact.Body(e.RequestContentType, e.RequestBody, l.bodyLimit),
+        redact.Body(e.ResponseContentType, e.ResponseBody, l.bodyLimit)
+}
+
+func orDash(s string) string {
+    if s == "" {
+        return "-"
+    }
+    return s
+}
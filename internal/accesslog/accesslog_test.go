@@ -0,0 +1,183 @@
+package accesslog
+
+import (
+    "bytes"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+func newTestLogger(format string, fields []string, bodyLimit int) (*Logger, *bytes.Buffer) {
+    out := &bytes.Buffer{}
+    l := &Logger{format: format, fields: make(map[string]bool), bodyLimit: bodyLimit, out: out}
+    for _, field := range fields {
+        l.fields[field] = true
+    }
+    return l, out
+}
+
+func TestLog(t *testing.T) {
+    at := time.Date(2024, 3, 9, 14, 5, 6, 0, time.FixedZone("", 2*60*60))
+    entry := Entry{
+        Time:      at,
+        Level:     "info",
+        RemoteIP:  "203.0.113.7",
+        Method:    "POST",
+        URI:       "/users?page=2",
+        Protocol:  "HTTP/1.1",
+        Route:     "/users",
+        Status:    201,
+        Duration:  1500 * time.Millisecond,
+        BytesIn:   12,
+        BytesOut:  34,
+        Referer:   "https://example.com/",
+        UserAgent: "curl/8.0",
+        RequestID: "req-1",
+        
+        RequestContentType:  "application/json",
+        RequestBody:         []byte(`{"id":1}`),
+        ResponseContentType: "text/plain",
+        ResponseBody:        []byte("created"),
+    }
+    allFields := []string{FieldUserAgent, FieldReferer, FieldBytesIn, FieldBytesOut, FieldRoute}
+    
+    tests := []struct {
+        name      string
+        format    string
+        fields    []string
+        bodyLimit int
+        modify    func(e *Entry)
+        want      string
+    }{
+        {
+            name:      "combined",
+            format:    FormatCombined,
+            bodyLimit: -1,
+            want:      `203.0.113.7 - - [09/Mar/2024:14:05:06 +0200] "POST /users?page=2 HTTP/1.1" 201 34 "https://example.com/" "curl/8.0"`,
+        },
+        {
+            name:      "combined principal and dashes",
+            format:    FormatCombined,
+            bodyLimit: -1,
+            modify: func(e *Entry) {
+                e.Principal, e.RemoteIP, e.Referer, e.UserAgent, e.BytesOut = "alice", "", "", "", -1
+            },
+            want: `- - alice [09/Mar/2024:14:05:06 +0200] "POST /users?page=2 HTTP/1.1" 201 - "-" "-"`,
+        },
+        {
+            name:      "combined ignores bodies",
+            format:    FormatCombined,
+            fields:    allFields,
+            bodyLimit: 100,
+            modify:    func(e *Entry) { e.BytesOut = 0 },
+            want:      `203.0.113.7 - - [09/Mar/2024:14:05:06 +0200] "POST /users?page=2 HTTP/1.1" 201 - "https://example.com/" "curl/8.0"`,
+        },
+        {
+            name:      "logfmt",
+            format:    FormatLogfmt,
+            bodyLimit: -1,
+            want:      `time=2024-03-09T14:05:06+02:00 level=info method=POST uri="/users?page=2" status=201 duration=1.5s ip=203.0.113.7 request_id=req-1`,
+        },
+        {
+            name:      "logfmt fields and bodies",
+            format:    FormatLogfmt,
+            fields:    allFields,
+            bodyLimit: 100,
+            modify:    func(e *Entry) { e.TraceID, e.Principal = "trace-1", "alice" },
+            want: `time=2024-03-09T14:05:06+02:00 level=info method=POST uri="/users?page=2" status=201 duration=1.5s ip=203.0.113.7 request_id=req-1 trace_id=trace-1 principal=alice ` +
+                `route=/users bytes_in=12 bytes_out=34 referer=https://example.com/ user_agent=curl/8.0 request_body="{\"id\":1}" response_body="[7 bytes omitted]"`,
+        },
+        {
+            name:      "logfmt quoting",
+            format:    FormatLogfmt,
+            fields:    []string{FieldUserAgent, FieldReferer},
+            bodyLimit: -1,
+            modify:    func(e *Entry) { e.UserAgent, e.Referer, e.URI = "Mozilla/5.0 (X11)", "", "/users?q=a=b\n\"c\"" },
+            want:      `time=2024-03-09T14:05:06+02:00 level=info method=POST uri="/users?q=a=b\n\"c\"" status=201 duration=1.5s ip=203.0.113.7 request_id=req-1 referer="" user_agent="Mozilla/5.0 (X11)"`,
+        },
+        {
+            name:      "logfmt truncated body",
+            format:    FormatLogfmt,
+            bodyLimit: 5,
+            modify:    func(e *Entry) { e.ResponseBody = nil },
+            want:      `time=2024-03-09T14:05:06+02:00 level=info method=POST uri="/users?page=2" status=201 duration=1.5s ip=203.0.113.7 request_id=req-1 request_body="{\"id\"...(truncated)" response_body=""`,
+        },
+        {
+            name:      "json",
+            format:    FormatJSON,
+            bodyLimit: -1,
+            want:      `{"time":"2024-03-09T14:05:06+02:00","level":"info","method":"POST","uri":"/users?page=2","status":201,"duration":1.5,"ip":"203.0.113.7","request_id":"req-1"}`,
+        },
+        {
+            name:      "json fields and bodies",
+            format:    FormatJSON,
+            fields:    allFields,
+            bodyLimit: 100,
+            modify:    func(e *Entry) { e.Referer, e.BytesOut = "", -1 },
+            want: `{"time":"2024-03-09T14:05:06+02:00","level":"info","method":"POST","uri":"/users?page=2","status":201,"duration":1.5,"ip":"203.0.113.7","request_id":"req-1",` +
+                `"route":"/users","bytes_in":12,"bytes_out":-1,"referer":"","user_agent":"curl/8.0","request_body":"{\"id\":1}","response_body":"[7 bytes omitted]"}`,
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            e := entry
+            if tt.modify != nil {
+                tt.modify(&e)
+            }
+            l, out := newTestLogger(tt.format, tt.fields, tt.bodyLimit)
+            l.Log(e)
+            if got := strings.TrimSuffix(out.String(), "\n"); got != tt.want {
+                t.Errorf("Log =\n%s\nwant\n%s", got, tt.want)
+            }
+            if !strings.HasSuffix(out.String(), "\n") {
+                t.Error("line does not end with a newline")
+            }
+        })
+    }
+}
+
+func TestNew(t *testing.T) {
+    tests := []struct {
+        name    string
+        cfg     Config
+        wantErr bool
+    }{
+        {"stdout", Config{Format: FormatJSON, Output: "stdout"}, false},
+        {"file", Config{Format: FormatCombined, Output: filepath.Join(t.TempDir(), "access.log")}, false},
+        {"unknown format", Config{Format: "xml", Output: "stdout"}, true},
+        {"no output", Config{Format: FormatLogfmt}, true},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            l, err := New(tt.cfg)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("New error = %v, want error %v", err, tt.wantErr)
+            }
+            if l != nil {
+                l.Close()
+            }
+        })
+    }
+}
+
+func TestDays(t *testing.T) {
+    tests := []struct {
+        maxAge time.Duration
+        want   int
+    }{
+        {0, 0},
+        {-time.Hour, 0},
+        {time.Hour, 1},
+        {24 * time.Hour, 1},
+        {25 * time.Hour, 2},
+    }
+    
+    for _, tt := range tests {
+        if got := days(tt.maxAge); got != tt.want {
+            t.Errorf("days(%v) = %d, want %d", tt.maxAge, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+    "context"
+    "crypto/sha256"
+    "crypto/subtle"
+    "crypto/x509"
+    "errors"
+    "slices"
+)
+
+// ScopeAdmin grants access to operational endpoints under /admin.
+const ScopeAdmin = "admin"
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+    Subject string
+    // Method is how the caller authenticated, e.g. "api_key"
+    Method string
+    Scopes []string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+    return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+    return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal in ctx, or nil for anonymous requests.
+func FromContext(ctx context.Context) *Principal {
+    principal, _ := ctx.Value(contextKey{}).(*Principal)
+    return principal
+}
+
+type APIKey struct {
+    Name   string
+    Key    string
+    Scopes []string
+}
+
+// ClientCert grants scopes to callers presenting a verified client
+// certificate with Subject as its common name.
+type ClientCert struct {
+    Subject string
+    Scopes  []string
+}
+
+type apiKey struct {
+    digest    [sha256.Size]byte
+    principal *Principal
+}
+
+// Authenticator resolves API keys to principals. Keys are kept only as
+// digests and compared in constant time.
+type Authenticator struct {
+    keys       []apiKey
+    certScopes map[string][]string
+}
+
+func NewAuthenticator(keys []APIKey, certs []ClientCert) *Authenticator {
+    a := &Authenticator{
+        keys:       make([]apiKey, len(keys)),
+        certScopes: make(map[string][]string, len(certs)),
+    }
+    for i, key := range keys {
+        a.keys[i] = apiKey{
+            digest: sha256.Sum256([]byte(key.Key)),
+            principal: &Principal{
+                Subject: key.Name,
+                Method:  "api_key",
+                Scopes:  key.Scopes,
+            },
+        }
+    }
+    for _, cert := range certs {
+        a.certScopes[cert.Subject] = cert.Scopes
+    }
+    return a
+}
+
+func (a *Authenticator) AuthenticateAPIKey(key string) (*Principal, error) {
+    digest := sha256.Sum256([]byte(key))
+    var match *Principal
+    // Check every key so timing does not reveal which one matched
+    for _, candidate := range a.keys {
+        if subtle.ConstantTimeCompare(digest[:], candidate.digest[:]) == 1 {
+            match = candidate.principal
+        }
+    }
+    if match == nil {
+        return nil, ErrInvalidCredentials
+    }
+    return match, nil
+}
+
+// AuthenticateCertificate returns the principal for a client certificate
+// that TLS has already verified. Its subject is the certificate's common
+// name, or the full distinguished name without one; scopes come from the
+// matching ClientCert, if any.
+func (a *Authenticator) AuthenticateCertificate(cert *x509.Certificate) *Principal {
+    subject := cert.Subject.CommonName
+    if subject == "" {
+        subject = cert.Subject.String()
+    }
+    return &Principal{
+        Subject: subject,
+        Method:  "client_cert",
+        Scopes:  a.certScopes[subject],
+    }
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+    "container/list"
+    "sync"
+    "time"
+)
+
+// LRU is a size-bounded cache whose entries also expire after a fixed TTL.
+// A TTL of zero keeps entries until they are evicted or removed.
+type LRU[K comparable, V any] struct {
+    mu      sync.Mutex
+    size    int
+    ttl     time.Duration
+    items   map[K]*list.Element
+    order   *list.List
+    onEvict func(key K)
+    now     func() time.Time
+}
+
+type entry[K comparable, V any] struct {
+    key       K
+    value     V
+    expiresAt time.Time
+}
+
+// NewLRU panics if size is not positive. onEvict, when set, is called for
+// entries dropped to make room, not for expired or removed ones.
+func NewLRU[K comparable, V any](size int, ttl time.Duration, onEvict func(key K)) *LRU[K, V] {
+    if size <= 0 {
+        panic("cache: LRU size must be positive")
+    }
+    return &LRU[K, V]{
+        size:    size,
+        ttl:     ttl,
+        items:   make(map[K]*list.Element, size),
+        order:   list.New(),
+        onEvict: onEvict,
+        now:     time.Now,
+    }
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    var zero V
+    element, ok := c.items[key]
+    if !ok {
+        return zero, false
+    }
+    
+    e := element.Value.(*entry[K, V])
+    if c.expired(e) {
+        c.removeElement(element)
+        return zero, false
+    }
+    
+    c.order.MoveToFront(element)
+    return e.value, true
+}
+
+func (c *LRU[K, V]) Add(key K, value V) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    var expiresAt time.Time
+    if c.ttl > 0 {
+        expiresAt = c.now().Add(c.ttl)
+    }
+    
+    if element, ok := c.items[key]; ok {
+        e := element.Value.(*entry[K, V])
+        e.value = value
+        e.expiresAt = expiresAt
+        c.order.MoveToFront(element)
+        return
+    }
+    
+    c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+    
+    if c.order.Len() > c.size {
+        oldest := c.order.Back()
+        c.removeElement(oldest)
+        if c.onEvict != nil {
+            c.onEvict(oldest.Value.(*entry[K, V]).key)
+        }
+    }
+}
+
+func (c *LRU[K, V]) Remove(key K) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    if element, ok := c.items[key]; ok {
+        c.removeElement(element)
+    }
+}
+
+func (c *LRU[K, V]) Purge() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    c.items = make(map[K]*list.Element, c.size)
+    c.order.Init()
+}
+
+// Len includes expired entries that have not been looked up since expiring.
+func (c *LRU[K, V]) Len() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    return c.order.Len()
+}
+
+func (c *LRU[K, V]) expired(e *entry[K, V]) bool {
+    return !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt)
+}
+
+func (c *LRU[K, V]) removeElement(element *list.Element) {
+    c.order.Remove(element)
+    delete(c.items, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,232 @@
+package certs
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "os"
+    "slices"
+    "sync"
+    "sync/atomic"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+// Config describes the server certificate and, for mutual TLS, the CA
+// bundle client certificates are verified against.
+type Config struct {
+    CertFile string
+    KeyFile  string
+    // MinVersion is "1.2" or "1.3"
+    MinVersion string
+    // CipherSuites are Go cipher suite names for TLS 1.2; TLS 1.3 suites
+    // are not configurable. Empty keeps Go's defaults.
+    CipherSuites []string
+    ClientCAFile string
+    // ClientAuth is "none", "optional" or "require", and only applies with
+    // a ClientCAFile
+    ClientAuth string
+}
+
+var minVersions = map[string]uint16{
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+    "none":     tls.NoClientCert,
+    "optional": tls.VerifyClientCertIfGiven,
+    "require":  tls.RequireAndVerifyClientCert,
+}
+
+// Reloader serves the certificate and client CAs named in Config, loading
+// them again when the files change or on SIGHUP. Reloading only affects new
+// handshakes, so open connections are not dropped, and a failed reload
+// keeps the previous files in use.
+type Reloader struct {
+    cfg    Config
+    base   *tls.Config
+    logger *zap.Logger
+    
+    cert      atomic.Pointer[tls.Certificate]
+    clientCAs atomic.Pointer[x509.CertPool]
+    
+    mu       sync.Mutex
+    modTimes map[string]time.Time
+}
+
+// NewReloader checks cfg and loads its files.
+func NewReloader(cfg Config, logger *zap.Logger) (*Reloader, error) {
+    minVersion, ok := minVersions[cfg.MinVersion]
+    if !ok {
+        return nil, fmt.Errorf("unsupported minimum TLS version %q", cfg.MinVersion)
+    }
+    clientAuth, ok := clientAuthTypes[cfg.ClientAuth]
+    if !ok {
+        return nil, fmt.Errorf("unknown client auth mode %q", cfg.ClientAuth)
+    }
+    if cfg.ClientCAFile == "" {
+        if clientAuth == tls.RequireAndVerifyClientCert {
+            return nil, errors.New("requiring client certificates needs a client CA file")
+        }
+        clientAuth = tls.NoClientCert
+    }
+    suites, err := cipherSuites(cfg.CipherSuites)
+    if err != nil {
+        return nil, err
+    }
+    
+    r := &Reloader{
+        cfg:    cfg,
+        logger: logger,
+        base: &tls.Config{
+            MinVersion:   minVersion,
+            CipherSuites: suites,
+            ClientAuth:   clientAuth,
+        },
+    }
+    if err := r.Reload(); err != nil {
+        return nil, err
+    }
+    return r, nil
+}
+
+// TLSConfig returns a server config that always presents the most recently
+// loaded certificate and verifies clients against the current CA bundle.
+func (r *Reloader) TLSConfig() *tls.Config {
+    config := r.base.Clone()
+    config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+        current := r.base.Clone()
+        current.Certificates = []tls.Certificate{*r.cert.Load()}
+        current.ClientCAs = r.clientCAs.Load()
+        return current, nil
+    }
+    return config
+}
+
+// Reload loads the certificate, key and client CAs from disk.
+func (r *Reloader) Reload() error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    // Recorded up front so a bad file is retried on its next change, not
+    // on every check
+    r.modTimes = r.stat()
+    cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+    if err != nil {
+        return fmt.Errorf("load TLS certificate: %w", err)
+    }
+    if cert.Leaf == nil {
+        if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+            return fmt.Errorf("parse TLS certificate: %w", err)
+        }
+    }
+    
+    var pool *x509.CertPool
+    if r.cfg.ClientCAFile != "" {
+        pem, err := os.ReadFile(r.cfg.ClientCAFile)
+        if err != nil {
+            return fmt.Errorf("read client CA file: %w", err)
+        }
+        pool = x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(pem) {
+            return fmt.Errorf("no certificates in client CA file %s", r.cfg.ClientCAFile)
+        }
+    }
+    
+    r.cert.Store(&cert)
+    r.clientCAs.Store(pool)
+    r.logger.Info("TLS certificate loaded",
+        zap.String("subject", cert.Leaf.Subject.String()),
+        zap.Time("not_after", cert.Leaf.NotAfter),
+    )
+    return nil
+}
+
+// Watch reloads whenever one of the files changes, checking every
+// interval, or the process receives SIGHUP, until ctx is done. Failed
+// reloads are logged and retried on the next change.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) error {
+    signals := make(chan os.Signal, 1)
+    stop := notifyReload(signals)
+    defer stop()
+    
+    var tick <-chan time.Time
+    if interval > 0 {
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        tick = ticker.C
+    }
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-signals:
+            r.reload("signal")
+        case <-tick:
+            if r.changed() {
+                r.reload("file change")
+            }
+        }
+    }
+}
+
+func (r *Reloader) reload(trigger string) {
+    if err := r.Reload(); err != nil {
+        r.logger.Error("TLS certificate reload failed; keeping the previous one", zap.String("trigger", trigger), zap.Error(err))
+    }
+}
+
+// changed reports whether any file's modification time differs from when
+// it was last loaded.
+func (r *Reloader) changed() bool {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    for name, modTime := range r.stat() {
+        if !modTime.Equal(r.modTimes[name]) {
+            return true
+        }
+    }
+    return false
+}
+
+// stat returns the modification time of each file, following symlinks so
+// that swapped Kubernetes secret mounts are noticed. Missing files are left
+// out, which counts as a change once they reappear.
+func (r *Reloader) stat() map[string]time.Time {
+    modTimes := make(map[string]time.Time)
+    for _, name := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+        if name == "" {
+            continue
+        }
+        if info, err := os.Stat(name); err == nil {
+            modTimes[name] = info.ModTime()
+        }
+    }
+    return modTimes
+}
+
+func cipherSuites(names []string) ([]uint16, error) {
+    if len(names) == 0 {
+        return nil, nil
+    }
+    
+    // Only suites Go considers secure are accepted
+    available := tls.CipherSuites()
+    ids := make([]uint16, 0, len(names))
+    for _, name := range names {
+        i := slices.IndexFunc(available, func(suite *tls.CipherSuite) bool {
+            return suite.Name == name
+        })
+        if i < 0 {
+            return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+        }
+        ids = append(ids, available[i].ID)
+    }
+    return ids, nil
+}
@@ -0,0 +1,175 @@
+package certs
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+// writeCert writes a self-signed certificate for commonName and its key,
+// stamped with modTime so reloads do not depend on file system timestamp
+// resolution.
+func writeCert(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(time.Now().UnixNano()),
+        Subject:               pkix.Name{CommonName: commonName},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        IsCA:                  true,
+        BasicConstraintsValid: true,
+        KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    keyDER, err := x509.MarshalECPrivateKey(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), modTime)
+    writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), modTime)
+}
+
+func writeFile(t *testing.T, name string, data []byte, modTime time.Time) {
+    t.Helper()
+    if err := os.WriteFile(name, data, 0o600); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chtimes(name, modTime, modTime); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// served returns the common name of the certificate a new handshake gets.
+func served(t *testing.T, r *Reloader) string {
+    t.Helper()
+    config, err := r.TLSConfig().GetConfigForClient(nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return config.Certificates[0].Leaf.Subject.CommonName
+}
+
+func TestNewReloader(t *testing.T) {
+    dir := t.TempDir()
+    certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+    writeCert(t, certFile, keyFile, "server", time.Now())
+    
+    tests := []struct {
+        name    string
+        modify  func(cfg *Config)
+        wantErr string
+    }{
+        {"valid", func(cfg *Config) {}, ""},
+        {"client CA", func(cfg *Config) { cfg.ClientCAFile, cfg.ClientAuth = certFile, "require" }, ""},
+        {"unsupported version", func(cfg *Config) { cfg.MinVersion = "1.1" }, "unsupported minimum TLS version"},
+        {"unknown client auth", func(cfg *Config) { cfg.ClientAuth = "always" }, "unknown client auth mode"},
+        {"require without CA", func(cfg *Config) { cfg.ClientAuth = "require" }, "needs a client CA file"},
+        {"insecure cipher suite", func(cfg *Config) { cfg.CipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"} }, "unknown or insecure cipher suite"},
+        {"missing key", func(cfg *Config) { cfg.KeyFile = filepath.Join(dir, "missing.key") }, "load TLS certificate"},
+        {"CA file without certificates", func(cfg *Config) { cfg.ClientCAFile, cfg.ClientAuth = keyFile, "optional" }, "no certificates in client CA file"},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cfg := Config{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2", ClientAuth: "none"}
+            tt.modify(&cfg)
+            _, err := NewReloader(cfg, zap.NewNop())
+            switch {
+            case tt.wantErr == "" && err != nil:
+                t.Errorf("NewReloader: %v", err)
+            case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+                t.Errorf("NewReloader error = %v, want one containing %q", err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestReload(t *testing.T) {
+    dir := t.TempDir()
+    certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+    start := time.Now().Add(-time.Hour)
+    writeCert(t, certFile, keyFile, "first", start)
+    
+    r, err := NewReloader(Config{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2", ClientAuth: "none"}, zap.NewNop())
+    if err != nil {
+        t.Fatalf("NewReloader: %v", err)
+    }
+    
+    steps := []struct {
+        name        string
+        change      func(modTime time.Time)
+        wantChanged bool
+        wantErr     bool
+        wantServed  string
+    }{
+        {"unchanged", func(time.Time) {}, false, false, "first"},
+        {"replaced", func(modTime time.Time) { writeCert(t, certFile, keyFile, "second", modTime) }, true, false, "second"},
+        {"broken keeps the previous", func(modTime time.Time) { writeFile(t, certFile, []byte("not a certificate"), modTime) }, true, true, "second"},
+        {"fixed", func(modTime time.Time) { writeCert(t, certFile, keyFile, "third", modTime) }, true, false, "third"},
+    }
+    
+    for i, step := range steps {
+        step.change(start.Add(time.Duration(i+1) * time.Minute))
+        if changed := r.changed(); changed != step.wantChanged {
+            t.Errorf("%s: changed = %v, want %v", step.name, changed, step.wantChanged)
+        }
+        if err := r.Reload(); (err != nil) != step.wantErr {
+            t.Errorf("%s: Reload error = %v, want error %v", step.name, err, step.wantErr)
+        }
+        // Failed or not, the reload recorded the files it saw
+        if r.changed() {
+            t.Errorf("%s: still changed after Reload", step.name)
+        }
+        if got := served(t, r); got != step.wantServed {
+            t.Errorf("%s: serving %q, want %q", step.name, got, step.wantServed)
+        }
+    }
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+    dir := t.TempDir()
+    certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+    start := time.Now().Add(-time.Hour)
+    writeCert(t, certFile, keyFile, "before", start)
+    
+    r, err := NewReloader(Config{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3", ClientAuth: "none"}, zap.NewNop())
+    if err != nil {
+        t.Fatalf("NewReloader: %v", err)
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan error, 1)
+    go func() { done <- r.Watch(ctx, 10*time.Millisecond) }()
+    
+    writeCert(t, certFile, keyFile, "after", start.Add(time.Minute))
+    deadline := time.Now().Add(5 * time.Second)
+    for served(t, r) != "after" {
+        if time.Now().After(deadline) {
+            t.Fatal("certificate was not reloaded")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    
+    cancel()
+    if err := <-done; err != nil {
+        t.Errorf("Watch: %v", err)
+    }
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package certs
+
+import (
+    "os"
+)
+
+// notifyReload does nothing; there is no SIGHUP on this platform, so
+// certificates are only reloaded when their files change.
+func notifyReload(chan<- os.Signal) func() {
+    return func() {}
+}
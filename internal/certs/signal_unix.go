@@ -0,0 +1,15 @@
+//go:build unix
+
+package certs
+
+import (
+    "os"
+    "os/signal"
+    "syscall"
+)
+
+// notifyReload sends SIGHUP to signals until the returned func is called.
+func notifyReload(signals chan<- os.Signal) func() {
+    signal.Notify(signals, syscall.SIGHUP)
+    return func() { signal.Stop(signals) }
+}
@@ -0,0 +1,209 @@
+package clientip
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/netip"
+    "strings"
+)
+
+// Headers a client address can be taken from, as named in config.
+const (
+    HeaderForwarded     = "forwarded"
+    HeaderXForwardedFor = "x-forwarded-for"
+    HeaderXRealIP       = "x-real-ip"
+)
+
+// ErrUntrustedPeer is returned when forwarding headers arrive from a peer
+// that is not a trusted proxy, and so may have been forged.
+var ErrUntrustedPeer = errors.New("forwarding headers from an untrusted peer")
+
+// Client is where a request came from, as far as trusted proxies tell.
+type Client struct {
+    IP string
+    // Scheme is "http" or "https" as the client connected
+    Scheme string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying client.
+func NewContext(ctx context.Context, client Client) context.Context {
+    return context.WithValue(ctx, contextKey{}, client)
+}
+
+// FromContext returns the client in ctx, or the zero Client if there is
+// none.
+func FromContext(ctx context.Context) Client {
+    client, _ := ctx.Value(contextKey{}).(Client)
+    return client
+}
+
+// hop is one address in a forwarding chain and the scheme it connected
+// with, if known.
+type hop struct {
+    addr   string
+    scheme string
+}
+
+// Resolver finds the client behind a chain of trusted proxies.
+type Resolver struct {
+    trusted []netip.Prefix
+    headers []string
+}
+
+// NewResolver trusts peers within trustedProxies, given as IPs or CIDRs,
+// to report the client through headers, which are tried in order.
+func NewResolver(trustedProxies, headers []string) (*Resolver, error) {
+    r := &Resolver{headers: headers}
+    for _, proxy := range trustedProxies {
+        prefix, err := netip.ParsePrefix(proxy)
+        if err != nil {
+            addr, addrErr := netip.ParseAddr(proxy)
+            if addrErr != nil {
+                return nil, fmt.Errorf("trusted proxy %q is not an IP or CIDR", proxy)
+            }
+            prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+        }
+        r.trusted = append(r.trusted, prefix.Masked())
+    }
+    for _, header := range headers {
+        switch header {
+        case HeaderForwarded, HeaderXForwardedFor, HeaderXRealIP:
+        default:
+            return nil, fmt.Errorf("unknown client IP header %q", header)
+        }
+    }
+    return r, nil
+}
+
+// Resolve returns the client of a request that arrived from peer over
+// scheme. Forwarding headers, read through header, are only believed when
+// peer is a trusted proxy; the chain is then walked back from the nearest
+// hop until an address that is not a trusted proxy. If an untrusted peer
+// sent forwarding headers they are ignored and ErrUntrustedPeer is
+// returned alongside the peer as the client.
+func (r *Resolver) Resolve(peer, scheme string, header func(string) string) (Client, error) {
+    client := Client{IP: peer, Scheme: scheme}
+    peerAddr, err := netip.ParseAddr(peer)
+    if err != nil {
+        return client, nil
+    }
+    client.IP = peerAddr.Unmap().String()
+    
+    if !r.isTrusted(peerAddr) {
+        for _, name := range r.headers {
+            if header(name) != "" {
+                return client, ErrUntrustedPeer
+            }
+        }
+        return client, nil
+    }
+    
+    for _, name := range r.headers {
+        chain := r.chain(name, header)
+        if len(chain) == 0 {
+            continue
+        }
+        for i := len(chain) - 1; i >= 0; i-- {
+            addr, ok := parseHost(chain[i].addr)
+            if !ok {
+                // Nothing before a malformed entry can be trusted
+                break
+            }
+            client.IP = addr.String()
+            if chain[i].scheme != "" {
+                client.Scheme = chain[i].scheme
+            }
+            if !r.isTrusted(addr) {
+                break
+            }
+        }
+        return client, nil
+    }
+    return client, nil
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+    addr = addr.Unmap()
+    for _, prefix := range r.trusted {
+        if prefix.Contains(addr) {
+            return true
+        }
+    }
+    return false
+}
+
+// chain returns the hops recorded in one header, client first.
+func (r *Resolver) chain(name string, header func(string) string) []hop {
+    value := header(name)
+    if value == "" {
+        return nil
+    }
+    
+    switch name {
+    case HeaderForwarded:
+        return parseForwarded(value)
+    case HeaderXForwardedFor:
+        // X-Forwarded-Proto carries one scheme for the whole chain, the
+        // one the client connected with
+        scheme, _, _ := strings.Cut(header("x-forwarded-proto"), ",")
+        scheme = normalizeScheme(scheme)
+        var hops []hop
+        for _, addr := range strings.Split(value, ",") {
+            hops = append(hops, hop{addr: strings.TrimSpace(addr)})
+        }
+        hops[len(hops)-1].scheme = scheme
+        return hops
+    default:
+        return []hop{{addr: strings.TrimSpace(value)}}
+    }
+}
+
+// parseForwarded reads the for and proto parameters of an RFC 7239
+// Forwarded header.
+func parseForwarded(value string) []hop {
+    var hops []hop
+    for _, element := range strings.Split(value, ",") {
+        var h hop
+        for _, pair := range strings.Split(element, ";") {
+            key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+            if !ok {
+                continue
+            }
+            val = strings.Trim(val, `"`)
+            switch strings.ToLower(key) {
+            case "for":
+                h.addr = val
+            case "proto":
+                h.scheme = normalizeScheme(val)
+            }
+        }
+        hops = append(hops, h)
+    }
+    return hops
+}
+
+// parseHost parses an address that may carry a port, with IPv6 in
+// brackets as in "[2001:db8::1]:443". Obfuscated and "unknown" identifiers
+// are rejected.
+func parseHost(host string) (netip.Addr, bool) {
+    if addrPort, err := netip.ParseAddrPort(host); err == nil {
+        return addrPort.Addr().Unmap(), true
+    }
+    addr, err := netip.ParseAddr(strings.Trim(host, "[]"))
+    if err != nil {
+        return netip.Addr{}, false
+    }
+    return addr.Unmap(), true
+}
+
+func normalizeScheme(scheme string) string {
+    switch scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme {
+    case "http", "https":
+        return scheme
+    default:
+        return ""
+    }
+}
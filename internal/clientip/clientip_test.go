@@ -0,0 +1,206 @@
+package clientip
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestResolve(t *testing.T) {
+    resolver, err := NewResolver(
+        []string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"},
+        []string{HeaderXForwardedFor, HeaderForwarded, HeaderXRealIP},
+    )
+    if err != nil {
+        t.Fatalf("NewResolver: %v", err)
+    }
+    
+    tests := []struct {
+        name       string
+        peer       string
+        scheme     string
+        headers    map[string]string
+        wantIP     string
+        wantScheme string
+        wantErr    error
+    }{
+        {
+            name:       "untrusted peer without headers",
+            peer:       "203.0.113.5",
+            scheme:     "http",
+            wantIP:     "203.0.113.5",
+            wantScheme: "http",
+        },
+        {
+            name:       "untrusted peer with forged header",
+            peer:       "203.0.113.5",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1", "x-forwarded-proto": "https"},
+            wantIP:     "203.0.113.5",
+            wantScheme: "http",
+            wantErr:    ErrUntrustedPeer,
+        },
+        {
+            name:       "trusted peer without headers",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            wantIP:     "10.1.2.3",
+            wantScheme: "http",
+        },
+        {
+            name:       "trusted peer with single hop",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1", "x-forwarded-proto": "https"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "https",
+        },
+        {
+            name:       "chain walks past trusted proxies",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1, 192.168.1.1, 10.9.9.9"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "http",
+        },
+        {
+            name:       "chain stops at the first untrusted hop",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "1.1.1.1, 198.51.100.1, 10.9.9.9"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "http",
+        },
+        {
+            name:       "chain of only trusted proxies ends at the farthest",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "10.7.7.7, 10.8.8.8"},
+            wantIP:     "10.7.7.7",
+            wantScheme: "http",
+        },
+        {
+            name:       "malformed entry stops the walk",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1, not-an-ip, 10.9.9.9"},
+            wantIP:     "10.9.9.9",
+            wantScheme: "http",
+        },
+        {
+            name:       "malformed nearest entry keeps the peer",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "garbage"},
+            wantIP:     "10.1.2.3",
+            wantScheme: "http",
+        },
+        {
+            name:       "unknown proto is ignored",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1", "x-forwarded-proto": "gopher"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "http",
+        },
+        {
+            name:       "forwarded header with ports and proto",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"forwarded": `for="[2001:db8::1]:4711";proto=https, for=10.9.9.9:80;proto=http`},
+            wantIP:     "2001:db8::1",
+            wantScheme: "https",
+        },
+        {
+            name:       "forwarded header with obfuscated identifier",
+            peer:       "10.1.2.3",
+            scheme:     "https",
+            headers:    map[string]string{"forwarded": "for=_hidden, for=10.9.9.9"},
+            wantIP:     "10.9.9.9",
+            wantScheme: "https",
+        },
+        {
+            name:       "forwarded header with unknown",
+            peer:       "10.1.2.3",
+            scheme:     "https",
+            headers:    map[string]string{"forwarded": "for=unknown"},
+            wantIP:     "10.1.2.3",
+            wantScheme: "https",
+        },
+        {
+            name:       "headers are tried in order",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"forwarded": "for=198.51.100.2", "x-forwarded-for": "198.51.100.1"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "http",
+        },
+        {
+            name:       "later header when earlier ones are absent",
+            peer:       "10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-real-ip": " 198.51.100.3 "},
+            wantIP:     "198.51.100.3",
+            wantScheme: "http",
+        },
+        {
+            name:       "IPv4-mapped peer is trusted",
+            peer:       "::ffff:10.1.2.3",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "::ffff:198.51.100.1"},
+            wantIP:     "198.51.100.1",
+            wantScheme: "http",
+        },
+        {
+            name:       "trusted IPv6 peer",
+            peer:       "fd00::1",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "2001:db8::2"},
+            wantIP:     "2001:db8::2",
+            wantScheme: "http",
+        },
+        {
+            name:       "unparsable peer is passed through",
+            peer:       "pipe",
+            scheme:     "http",
+            headers:    map[string]string{"x-forwarded-for": "198.51.100.1"},
+            wantIP:     "pipe",
+            wantScheme: "http",
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            client, err := resolver.Resolve(tt.peer, tt.scheme, func(name string) string {
+                return tt.headers[name]
+            })
+            if !errors.Is(err, tt.wantErr) {
+                t.Errorf("err = %v, want %v", err, tt.wantErr)
+            }
+            if client.IP != tt.wantIP || client.Scheme != tt.wantScheme {
+                t.Errorf("client = %+v, want IP %q and scheme %q", client, tt.wantIP, tt.wantScheme)
+            }
+        })
+    }
+}
+
+func TestNewResolver(t *testing.T) {
+    tests := []struct {
+        name    string
+        proxies []string
+        headers []string
+        wantErr bool
+    }{
+        {"IPs and CIDRs", []string{"10.0.0.1", "10.0.0.0/8", "::1", "fd00::/8"}, []string{HeaderXRealIP}, false},
+        {"invalid proxy", []string{"10.0.0.300"}, nil, true},
+        {"unknown header", nil, []string{"x-client-ip"}, true},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := NewResolver(tt.proxies, tt.headers)
+            if (err != nil) != tt.wantErr {
+                t.Errorf("err = %v, want error %v", err, tt.wantErr)
+            }
+        })
+    }
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "io/fs"
+    
+    "github.com/adityaK87/go-backend-assignment/db/migrations"
+    "github.com/adityaK87/go-backend-assignment/db/sqlite"
+)
+
+// Migrations returns the migrations built into the binary for driver.
+func Migrations(driver string) (fs.FS, error) {
+    if driver == DriverSQLite {
+        return fs.Sub(sqlite.Migrations, "migrations")
+    }
+    return migrations.FS, nil
+}
+
+// PingCheck reports whether the database answers.
+func PingCheck(db *sql.DB) func(ctx context.Context) error {
+    return db.PingContext
+}
+
+// MigrationCheck fails while the recorded schema version is behind the
+// newest migration built into the binary, or a migration failed halfway. A
+// newer schema is accepted so old replicas keep serving during a rollout.
+// Its errors say how to catch up, since the check is often the first sign
+// that an upgrade skipped the migrations.
+func MigrationCheck(db *sql.DB, driver string) func(ctx context.Context) error {
+    return func(ctx context.Context) error {
+        migrations, err := Migrations(driver)
+        if err != nil {
+            return err
+        }
+        files, err := MigrationFiles(migrations)
+        if err != nil {
+            return err
+        }
+        if len(files) == 0 {
+            return nil
+        }
+        expected := files[len(files)-1].Version
+        
+        version, dirty, err := CurrentVersion(ctx, db)
+        if err != nil {
+            return fmt.Errorf("read schema version: %w", err)
+        }
+        
+        switch {
+        case version == 0:
+            return fmt.Errorf("no migrations applied, expected version %d; apply db/migrations or set database.migrate_on_start", expected)
+        case dirty:
+            return fmt.Errorf("migration %d failed and left the schema dirty; fix the schema and reset schema_migrations before migrating again", version)
+        case version < expected:
+            return fmt.Errorf("schema version %d is behind expected version %d; apply db/migrations or set database.migrate_on_start", version, expected)
+        }
+        return nil
+    }
+}
+
+// PoolCheck fails when at least threshold (0-1] of the maximum open
+// connections are in use and callers have had to wait for one since the
+// previous check. Pools without a limit never saturate.
+func PoolCheck(db *sql.DB, threshold float64) func(ctx context.Context) error {
+    var lastWaitCount int64
+    return func(ctx context.Context) error {
+        stats := db.Stats()
+        waited := stats.WaitCount > lastWaitCount
+        lastWaitCount = stats.WaitCount
+        
+        if stats.MaxOpenConnections <= 0 || !waited {
+            return nil
+        }
+        if float64(stats.InUse) >= threshold*float64(stats.MaxOpenConnections) {
+            return fmt.Errorf("connection pool saturated: %d of %d in use", stats.InUse, stats.MaxOpenConnections)
+        }
+        return nil
+    }
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+    "database/sql"
+    "fmt"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+    
+    _ "github.com/lib/pq"
+    _ "modernc.org/sqlite"
+)
+
+const (
+    DriverPostgres = "postgres"
+    DriverSQLite   = "sqlite"
+    
+    sqliteScheme = "sqlite://"
+)
+
+// Driver picks the storage backend from the scheme of databaseURL.
+// sqlite://path selects SQLite; anything else is handed to Postgres.
+func Driver(databaseURL string) string {
+    if strings.HasPrefix(databaseURL, sqliteScheme) {
+        return DriverSQLite
+    }
+    return DriverPostgres
+}
+
+// Open connects to the backend selected by databaseURL. For Postgres a
+// non-zero statementTimeout becomes the session default, so a runaway query
+// is cancelled by the server even when no request deadline applies.
+func Open(databaseURL string, statementTimeout time.Duration) (*sql.DB, string, error) {
+    driver := Driver(databaseURL)
+    if driver == DriverPostgres {
+        dsn, err := withStatementTimeout(databaseURL, statementTimeout)
+        if err != nil {
+            return nil, driver, err
+        }
+        db, err := sql.Open("postgres", dsn)
+        return db, driver, err
+    }
+    
+    path := strings.TrimPrefix(databaseURL, sqliteScheme)
+    if path == "" {
+        return nil, driver, fmt.Errorf("sqlite database URL %q has no path", databaseURL)
+    }
+    
+    // Immediate transactions avoid SQLITE_BUSY on lock upgrades, and WAL lets
+    // readers proceed while a write is in progress
+    separator := "?"
+    if strings.Contains(path, "?") {
+        separator = "&"
+    }
+    dsn := path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate&_time_format=sqlite"
+    
+    db, err := sql.Open("sqlite", dsn)
+    if err != nil {
+        return nil, driver, err
+    }
+    
+    migrations, err := Migrations(driver)
+    if err != nil {
+        db.Close()
+        return nil, driver, err
+    }
+    if err := Migrate(db, migrations); err != nil {
+        db.Close()
+        return nil, driver, fmt.Errorf("migrate sqlite database: %w", err)
+    }
+    return db, driver, nil
+}
+
+// withStatementTimeout adds statement_timeout to a Postgres DSN in either URL
+// or key=value form. lib/pq forwards unknown keys as session parameters.
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+    if timeout <= 0 {
+        return dsn, nil
+    }
+    ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+    
+    if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+        return strings.TrimSpace(dsn) + " statement_timeout=" + ms, nil
+    }
+    u, err := url.Parse(dsn)
+    if err != nil {
+        return "", fmt.Errorf("parse database URL: %w", err)
+    }
+    query := u.Query()
+    if query.Get("statement_timeout") == "" {
+        query.Set("statement_timeout", ms)
+    }
+    u.RawQuery = query.Encode()
+    return u.String(), nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "io/fs"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// migrationLockKey is the Postgres advisory lock taken while migrating. Any
+// fixed key works as long as every replica uses the same one.
+const migrationLockKey = 0x75736572 // "user"
+
+// conn is the part of *sql.DB and *sql.Conn that migrations need.
+type conn interface {
+    ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+    QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+    BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Migrate applies NNN_name.sql files from migrations that are newer than the
+// recorded version. Versions are tracked in a single-row schema_migrations
+// table, the same layout golang-migrate uses, so the two can be mixed.
+func Migrate(db *sql.DB, migrations fs.FS) error {
+    return migrate(context.Background(), db, migrations)
+}
+
+// MigratePostgres is Migrate for a database shared by several replicas. It
+// holds an advisory lock throughout, so replicas starting together take
+// turns and the later ones find the schema already up to date.
+func MigratePostgres(ctx context.Context, db *sql.DB, migrations fs.FS) error {
+    c, err := db.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer c.Close()
+    
+    if _, err := c.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+        return fmt.Errorf("lock migrations: %w", err)
+    }
+    defer c.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+    return migrate(ctx, c, migrations)
+}
+
+func migrate(ctx context.Context, db conn, migrations fs.FS) error {
+    if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL, dirty BOOLEAN NOT NULL)`); err != nil {
+        return err
+    }
+    
+    current, dirty, err := currentVersion(ctx, db)
+    if err != nil {
+        return err
+    }
+    if dirty {
+        return fmt.Errorf("database is dirty at version %d", current)
+    }
+    
+    files, err := MigrationFiles(migrations)
+    if err != nil {
+        return err
+    }
+    
+    for _, file := range files {
+        if file.Version <= current {
+            continue
+        }
+        
+        contents, err := fs.ReadFile(migrations, file.Name)
+        if err != nil {
+            return err
+        }
+        
+        tx, err := db.BeginTx(ctx, nil)
+        if err != nil {
+            return err
+        }
+        if _, err := tx.ExecContext(ctx, string(contents)); err != nil {
+            tx.Rollback()
+            return fmt.Errorf("%s: %w", file.Name, err)
+        }
+        if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+            tx.Rollback()
+            return err
+        }
+        if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, file.Version); err != nil {
+            tx.Rollback()
+            return err
+        }
+        if err := tx.Commit(); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// CurrentVersion returns 0 when no migration has been recorded.
+func CurrentVersion(ctx context.Context, db *sql.DB) (int64, bool, error) {
+    return currentVersion(ctx, db)
+}
+
+func currentVersion(ctx context.Context, db conn) (int64, bool, error) {
+    var version int64
+    var dirty bool
+    err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+    if err == sql.ErrNoRows {
+        return 0, false, nil
+    }
+    return version, dirty, err
+}
+
+type MigrationFile struct {
+    Version int64
+    Name    string
+}
+
+// MigrationFiles lists the .sql files in migrations ordered by the numeric
+// prefix of their names.
+func MigrationFiles(migrations fs.FS) ([]MigrationFile, error) {
+    entries, err := fs.ReadDir(migrations, ".")
+    if err != nil {
+        return nil, err
+    }
+    
+    var files []MigrationFile
+    for _, entry := range entries {
+        name := entry.Name()
+        if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+            continue
+        }
+        prefix, _, _ := strings.Cut(name, "_")
+        version, err := strconv.ParseInt(prefix, 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("migration %s has no numeric version prefix", name)
+        }
+        files = append(files, MigrationFile{Version: version, Name: name})
+    }
+    
+    sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+    return files, nil
+}
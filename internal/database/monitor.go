@@ -0,0 +1,145 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "math/rand/v2"
+    "sync"
+    "time"
+    
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "go.uber.org/zap"
+)
+
+const (
+    minStartupBackoff = 250 * time.Millisecond
+    maxStartupBackoff = 10 * time.Second
+)
+
+var databaseUp = promauto.NewGauge(prometheus.GaugeOpts{
+    Name: "database_up",
+    Help: "Whether the last database ping succeeded (1) or failed (0).",
+})
+
+// WaitForDatabase pings db with exponential backoff until it answers or
+// maxWait has passed, so the service can start before the database does.
+// Each ping is bounded by pingTimeout.
+func WaitForDatabase(ctx context.Context, db *sql.DB, maxWait, pingTimeout time.Duration, logger *zap.Logger) error {
+    deadline := time.Now().Add(maxWait)
+    backoff := minStartupBackoff
+    
+    for attempt := 1; ; attempt++ {
+        err := ping(ctx, db, pingTimeout)
+        if err == nil {
+            databaseUp.Set(1)
+            return nil
+        }
+        
+        // Full jitter keeps replicas started together from retrying in step
+        delay := rand.N(backoff) + 1
+        if time.Now().Add(delay).After(deadline) {
+            return fmt.Errorf("database not reachable after %d attempts: %w", attempt, err)
+        }
+        logger.Warn("Database not reachable yet, retrying",
+            zap.Int("attempt", attempt),
+            zap.Duration("retry_in", delay),
+            zap.Error(err),
+        )
+        
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-time.After(delay):
+        }
+        backoff = min(backoff*2, maxStartupBackoff)
+    }
+}
+
+// Monitor pings the database in the background. When pings fail the service
+// is reported as degraded rather than stopped, and it recovers on its own
+// once the database is back.
+type Monitor struct {
+    db       *sql.DB
+    interval time.Duration
+    timeout  time.Duration
+    logger   *zap.Logger
+    
+    mu      sync.Mutex
+    lastErr error
+}
+
+func NewMonitor(db *sql.DB, interval, timeout time.Duration, logger *zap.Logger) *Monitor {
+    return &Monitor{
+        db:       db,
+        interval: interval,
+        timeout:  timeout,
+        logger:   logger,
+    }
+}
+
+// Run pings every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+    ticker := time.NewTicker(m.interval)
+    defer ticker.Stop()
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            m.check(ctx)
+        }
+    }
+}
+
+func (m *Monitor) check(ctx context.Context) {
+    err := ping(ctx, m.db, m.timeout)
+    if ctx.Err() != nil {
+        return
+    }
+    
+    m.mu.Lock()
+    wasHealthy := m.lastErr == nil
+    m.lastErr = err
+    m.mu.Unlock()
+    
+    switch {
+    case err != nil && wasHealthy:
+        databaseUp.Set(0)
+        m.logger.Error("Database unreachable, service degraded", zap.Error(err))
+    case err == nil && !wasHealthy:
+        databaseUp.Set(1)
+        m.logger.Info("Database reachable again")
+    }
+}
+
+// Err returns the error of the last background ping, or nil while the
+// database is reachable.
+func (m *Monitor) Err() error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    return m.lastErr
+}
+
+// Healthy reports whether the last background ping succeeded.
+func (m *Monitor) Healthy() bool {
+    return m.Err() == nil
+}
+
+// Check is a health check reporting the degraded state, for readiness, so
+// replicas that lost the database are taken out of rotation until it
+// returns.
+func (m *Monitor) Check(ctx context.Context) error {
+    if err := m.Err(); err != nil {
+        return fmt.Errorf("database unreachable since last ping: %w", err)
+    }
+    return nil
+}
+
+func ping(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+    return db.PingContext(ctx)
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+func TestMonitorReportsDegraded(t *testing.T) {
+    db, _, err := Open("sqlite://"+filepath.Join(t.TempDir(), "monitor.db"), 0)
+    if err != nil {
+        t.Fatalf("open sqlite: %v", err)
+    }
+    monitor := NewMonitor(db, time.Hour, time.Second, zap.NewNop())
+    ctx := context.Background()
+    
+    monitor.check(ctx)
+    if !monitor.Healthy() || monitor.Check(ctx) != nil {
+        t.Fatalf("reachable database reported degraded: %v", monitor.Err())
+    }
+    
+    db.Close()
+    monitor.check(ctx)
+    if monitor.Healthy() {
+        t.Fatal("closed database reported healthy")
+    }
+    if err := monitor.Check(ctx); err == nil {
+        t.Fatal("Check passed with the database unreachable")
+    }
+}
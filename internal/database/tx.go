@@ -0,0 +1,208 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "math/rand/v2"
+    "strings"
+    "time"
+)
+
+const (
+    // SQLSTATE codes for transactions worth retrying from the start
+    sqlStateSerializationFailure = "40001"
+    sqlStateDeadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+// txState is carried in the context of a running transaction so nested
+// calls and repositories can join it.
+type txState struct {
+    tx          *sql.Tx
+    afterCommit []func()
+}
+
+// TxManager runs functions as a single unit of work. Repositories pick the
+// transaction up from the context with TxFromContext.
+type TxManager interface {
+    WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+type TxOption func(*txConfig)
+
+type txConfig struct {
+    isolation  sql.IsolationLevel
+    readOnly   bool
+    maxRetries int
+}
+
+// WithIsolation overrides the manager's default isolation level.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+    return func(c *txConfig) { c.isolation = level }
+}
+
+func WithReadOnly() TxOption {
+    return func(c *txConfig) { c.readOnly = true }
+}
+
+// WithMaxRetries overrides how many times a serialization failure or deadlock
+// is retried before the error is returned.
+func WithMaxRetries(n int) TxOption {
+    return func(c *txConfig) { c.maxRetries = n }
+}
+
+type sqlTxManager struct {
+    db       *sql.DB
+    driver   string
+    defaults txConfig
+}
+
+// NewTxManager returns a TxManager for db. Transactions default to
+// isolation and are retried up to maxRetries times.
+func NewTxManager(db *sql.DB, driver string, isolation sql.IsolationLevel, maxRetries int) TxManager {
+    return &sqlTxManager{
+        db:     db,
+        driver: driver,
+        defaults: txConfig{
+            isolation:  isolation,
+            maxRetries: maxRetries,
+        },
+    }
+}
+
+// WithinTx commits when fn returns nil and rolls back otherwise. A call made
+// inside another WithinTx joins the outer transaction, whose options win, and
+// only the outermost call commits or retries.
+func (m *sqlTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+    if _, ok := ctx.Value(txKey{}).(*txState); ok {
+        return fn(ctx)
+    }
+    
+    config := m.defaults
+    for _, opt := range opts {
+        opt(&config)
+    }
+    
+    for attempt := 0; ; attempt++ {
+        err := m.run(ctx, config, fn)
+        if err == nil || attempt >= config.maxRetries || !IsRetryable(err) {
+            return err
+        }
+        
+        select {
+        case <-ctx.Done():
+            return err
+        case <-time.After(retryDelay(attempt)):
+        }
+    }
+}
+
+func (m *sqlTxManager) run(ctx context.Context, config txConfig, fn func(ctx context.Context) error) error {
+    options := &sql.TxOptions{Isolation: config.isolation, ReadOnly: config.readOnly}
+    // SQLite transactions are always serializable and the driver rejects
+    // any other level
+    if m.driver == DriverSQLite {
+        options = nil
+    }
+    
+    tx, err := m.db.BeginTx(ctx, options)
+    if err != nil {
+        return err
+    }
+    
+    if err := m.limitStatements(ctx, tx); err != nil {
+        _ = tx.Rollback()
+        return err
+    }
+    
+    state := &txState{tx: tx}
+    if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+        _ = tx.Rollback()
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+    
+    for _, hook := range state.afterCommit {
+        hook()
+    }
+    return nil
+}
+
+// limitStatements lowers the Postgres statement_timeout for the rest of tx
+// to whatever is left of the context deadline, so a query the client has
+// given up on is cancelled on the server rather than left running.
+func (m *sqlTxManager) limitStatements(ctx context.Context, tx *sql.Tx) error {
+    deadline, ok := ctx.Deadline()
+    if !ok || m.driver != DriverPostgres {
+        return nil
+    }
+    remaining := time.Until(deadline).Milliseconds()
+    if remaining < 1 {
+        return context.DeadlineExceeded
+    }
+    _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", remaining))
+    return err
+}
+
+// TxFromContext returns the transaction started by WithinTx, if any.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+    state, ok := ctx.Value(txKey{}).(*txState)
+    if !ok {
+        return nil, false
+    }
+    return state.tx, true
+}
+
+// AfterCommit runs hook once the transaction in ctx commits, or immediately
+// when there is none. Hooks are dropped if the transaction rolls back.
+func AfterCommit(ctx context.Context, hook func()) {
+    state, ok := ctx.Value(txKey{}).(*txState)
+    if !ok {
+        hook()
+        return
+    }
+    state.afterCommit = append(state.afterCommit, hook)
+}
+
+// IsRetryable reports whether err is a serialization failure or deadlock,
+// after which the whole transaction can be retried.
+func IsRetryable(err error) bool {
+    var sqlState interface{ SQLState() string }
+    if errors.As(err, &sqlState) {
+        code := sqlState.SQLState()
+        return code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+    }
+    return false
+}
+
+// ParseIsolation accepts names such as "read committed", "repeatable_read"
+// or "serializable". An empty name selects the driver default.
+func ParseIsolation(name string) (sql.IsolationLevel, error) {
+    normalized := strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(name)))
+    switch normalized {
+    case "", "default":
+        return sql.LevelDefault, nil
+    case "read uncommitted":
+        return sql.LevelReadUncommitted, nil
+    case "read committed":
+        return sql.LevelReadCommitted, nil
+    case "repeatable read":
+        return sql.LevelRepeatableRead, nil
+    case "serializable":
+        return sql.LevelSerializable, nil
+    default:
+        return sql.LevelDefault, fmt.Errorf("unknown isolation level %q", name)
+    }
+}
+
+// retryDelay backs off exponentially from 10ms, with jitter so retried
+// transactions do not collide again.
+func retryDelay(attempt int) time.Duration {
+    base := 10 * time.Millisecond << min(attempt, 6)
+    return base/2 + rand.N(base/2+1)
+}
@@ -0,0 +1,250 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "path/filepath"
+    "slices"
+    "testing"
+)
+
+// sqlStateError stands in for driver errors that carry a SQLSTATE code.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func newTestTxManager(t *testing.T, maxRetries int) (*sql.DB, TxManager) {
+    t.Helper()
+    db, driver, err := Open("sqlite://"+filepath.Join(t.TempDir(), "tx.db"), 0)
+    if err != nil {
+        t.Fatalf("open sqlite: %v", err)
+    }
+    t.Cleanup(func() { db.Close() })
+    if _, err := db.Exec(`CREATE TABLE items (name TEXT NOT NULL)`); err != nil {
+        t.Fatalf("create table: %v", err)
+    }
+    return db, NewTxManager(db, driver, sql.LevelDefault, maxRetries)
+}
+
+func insertItem(ctx context.Context, name string) error {
+    tx, ok := TxFromContext(ctx)
+    if !ok {
+        return errors.New("no transaction in context")
+    }
+    _, err := tx.ExecContext(ctx, `INSERT INTO items (name) VALUES (?)`, name)
+    return err
+}
+
+func itemNames(t *testing.T, db *sql.DB) []string {
+    t.Helper()
+    rows, err := db.Query(`SELECT name FROM items ORDER BY rowid`)
+    if err != nil {
+        t.Fatalf("query items: %v", err)
+    }
+    defer rows.Close()
+    var names []string
+    for rows.Next() {
+        var name string
+        if err := rows.Scan(&name); err != nil {
+            t.Fatalf("scan item: %v", err)
+        }
+        names = append(names, name)
+    }
+    return names
+}
+
+func TestWithinTxRetries(t *testing.T) {
+    tests := []struct {
+        name         string
+        maxRetries   int
+        opts         []TxOption
+        errs         []error
+        wantAttempts int
+        wantErr      error
+    }{
+        {"success", 3, nil, nil, 1, nil},
+        {"serialization failure then success", 3, nil, []error{sqlStateError("40001")}, 2, nil},
+        {"deadlocks then success", 3, nil, []error{sqlStateError("40P01"), sqlStateError("40P01")}, 3, nil},
+        {"wrapped code is retried", 3, nil, []error{fmt.Errorf("update: %w", sqlStateError("40001"))}, 2, nil},
+        {"retries exhausted", 2, nil, []error{sqlStateError("40001"), sqlStateError("40001"), sqlStateError("40001")}, 3, sqlStateError("40001")},
+        {"option overrides retries", 3, []TxOption{WithMaxRetries(0)}, []error{sqlStateError("40001")}, 1, sqlStateError("40001")},
+        {"other codes are not retried", 3, nil, []error{sqlStateError("23505")}, 1, sqlStateError("23505")},
+        {"plain errors are not retried", 3, nil, []error{sql.ErrNoRows}, 1, sql.ErrNoRows},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, manager := newTestTxManager(t, tt.maxRetries)
+            
+            attempts := 0
+            err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+                attempts++
+                if err := insertItem(ctx, fmt.Sprintf("attempt %d", attempts)); err != nil {
+                    return err
+                }
+                if attempts <= len(tt.errs) {
+                    return tt.errs[attempts-1]
+                }
+                return nil
+            }, tt.opts...)
+            
+            if !errors.Is(err, tt.wantErr) {
+                t.Errorf("err = %v, want %v", err, tt.wantErr)
+            }
+            if attempts != tt.wantAttempts {
+                t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+            }
+            // Only the successful attempt may leave a row behind
+            var want []string
+            if tt.wantErr == nil {
+                want = []string{fmt.Sprintf("attempt %d", attempts)}
+            }
+            if got := itemNames(t, db); !slices.Equal(got, want) {
+                t.Errorf("items = %v, want %v", got, want)
+            }
+        })
+    }
+}
+
+func TestWithinTxNested(t *testing.T) {
+    errInner := errors.New("inner failed")
+    errOuter := errors.New("outer failed")
+    
+    tests := []struct {
+        name      string
+        innerErr  error
+        outerErr  error
+        wantErr   error
+        wantItems []string
+    }{
+        {"both succeed", nil, nil, nil, []string{"outer", "inner"}},
+        {"inner fails", errInner, nil, errInner, nil},
+        {"outer fails after inner", nil, errOuter, errOuter, nil},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, manager := newTestTxManager(t, 3)
+            
+            outerAttempts, innerAttempts := 0, 0
+            err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+                outerAttempts++
+                outerTx, _ := TxFromContext(ctx)
+                if err := insertItem(ctx, "outer"); err != nil {
+                    return err
+                }
+                err := manager.WithinTx(ctx, func(ctx context.Context) error {
+                    innerAttempts++
+                    if innerTx, _ := TxFromContext(ctx); innerTx != outerTx {
+                        t.Error("nested call started its own transaction")
+                    }
+                    if err := insertItem(ctx, "inner"); err != nil {
+                        return err
+                    }
+                    return tt.innerErr
+                }, WithMaxRetries(5))
+                if err != nil {
+                    return err
+                }
+                return tt.outerErr
+            })
+            
+            if !errors.Is(err, tt.wantErr) {
+                t.Errorf("err = %v, want %v", err, tt.wantErr)
+            }
+            if outerAttempts != 1 || innerAttempts != 1 {
+                t.Errorf("attempts = %d outer, %d inner, want 1 each", outerAttempts, innerAttempts)
+            }
+            if got := itemNames(t, db); !slices.Equal(got, tt.wantItems) {
+                t.Errorf("items = %v, want %v", got, tt.wantItems)
+            }
+        })
+    }
+}
+
+func TestNestedRetryRestartsOuter(t *testing.T) {
+    _, manager := newTestTxManager(t, 1)
+    
+    outerAttempts, innerAttempts := 0, 0
+    err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+        outerAttempts++
+        return manager.WithinTx(ctx, func(ctx context.Context) error {
+            innerAttempts++
+            if innerAttempts == 1 {
+                return sqlStateError("40001")
+            }
+            return nil
+        })
+    })
+    if err != nil {
+        t.Fatalf("WithinTx: %v", err)
+    }
+    if outerAttempts != 2 || innerAttempts != 2 {
+        t.Errorf("attempts = %d outer, %d inner, want the outermost call to retry both", outerAttempts, innerAttempts)
+    }
+}
+
+func TestAfterCommit(t *testing.T) {
+    errFailed := errors.New("failed")
+    
+    tests := []struct {
+        name      string
+        fnErr     error
+        wantHooks []string
+    }{
+        {"commit runs hooks in order", nil, []string{"outer 1", "inner", "outer 2"}},
+        {"rollback drops hooks", errFailed, nil},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, manager := newTestTxManager(t, 0)
+            
+            var hooks []string
+            hook := func(name string) func() {
+                return func() {
+                    // Hooks must see the committed data from outside the
+                    // transaction
+                    if got := itemNames(t, db); !slices.Equal(got, []string{"row"}) {
+                        t.Errorf("hook %q ran before commit, items = %v", name, got)
+                    }
+                    hooks = append(hooks, name)
+                }
+            }
+            
+            err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+                if err := insertItem(ctx, "row"); err != nil {
+                    return err
+                }
+                AfterCommit(ctx, hook("outer 1"))
+                _ = manager.WithinTx(ctx, func(ctx context.Context) error {
+                    AfterCommit(ctx, hook("inner"))
+                    return nil
+                })
+                AfterCommit(ctx, hook("outer 2"))
+                if len(hooks) != 0 {
+                    t.Errorf("hooks ran inside the transaction: %v", hooks)
+                }
+                return tt.fnErr
+            })
+            
+            if !errors.Is(err, tt.fnErr) {
+                t.Errorf("err = %v, want %v", err, tt.fnErr)
+            }
+            if !slices.Equal(hooks, tt.wantHooks) {
+                t.Errorf("hooks = %v, want %v", hooks, tt.wantHooks)
+            }
+        })
+    }
+}
+
+func TestAfterCommitWithoutTransaction(t *testing.T) {
+    ran := false
+    AfterCommit(context.Background(), func() { ran = true })
+    if !ran {
+        t.Error("hook did not run immediately outside a transaction")
+    }
+}
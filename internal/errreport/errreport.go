@@ -0,0 +1,213 @@
+// Package errreport forwards panics and server errors to external sinks,
+// sending each distinct failure once per window rather than once per
+// request.
+package errreport
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "regexp"
+    "strings"
+    "sync"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+type Kind string
+
+const (
+    KindPanic Kind = "panic"
+    KindError Kind = "error"
+)
+
+// Report describes one failed request. Messages must already be redacted.
+type Report struct {
+    Kind    Kind      `json:"kind"`
+    Time    time.Time `json:"time"`
+    Message string    `json:"message"`
+    Stack   string    `json:"stack,omitempty"`
+    // Fingerprint groups reports of the same failure
+    Fingerprint string `json:"fingerprint"`
+    // Suppressed counts the duplicates dropped since the last report sent
+    // with this fingerprint
+    Suppressed int `json:"suppressed,omitempty"`
+    
+    RequestID string `json:"request_id,omitempty"`
+    TraceID   string `json:"trace_id,omitempty"`
+    Method    string `json:"method,omitempty"`
+    Path      string `json:"path,omitempty"`
+    Route     string `json:"route,omitempty"`
+    Status    int    `json:"status,omitempty"`
+}
+
+// Sink delivers reports somewhere outside the process.
+type Sink interface {
+    Send(ctx context.Context, report Report) error
+}
+
+// maxTracked bounds the fingerprints remembered for de-duplication.
+const maxTracked = 1024
+
+type seen struct {
+    last       time.Time
+    suppressed int
+}
+
+// Dispatcher de-duplicates reports and sends them to every sink from a
+// background worker, so a slow sink never holds up a request. Reports are
+// dropped when the queue is full.
+type Dispatcher struct {
+    sinks  []Sink
+    window time.Duration
+    queue  chan Report
+    logger *zap.Logger
+    
+    mu   sync.Mutex
+    seen map[string]*seen
+}
+
+func NewDispatcher(sinks []Sink, window time.Duration, queueSize int, logger *zap.Logger) *Dispatcher {
+    return &Dispatcher{
+        sinks:  sinks,
+        window: window,
+        queue:  make(chan Report, queueSize),
+        logger: logger,
+        seen:   make(map[string]*seen),
+    }
+}
+
+// Report queues r unless a report with the same fingerprint was queued
+// within the window. A missing fingerprint is derived from the stack, or
+// from the route, status and message when there is no stack.
+func (d *Dispatcher) Report(r Report) {
+    if d == nil || len(d.sinks) == 0 {
+        return
+    }
+    if r.Time.IsZero() {
+        r.Time = time.Now()
+    }
+    if r.Fingerprint == "" {
+        r.Fingerprint = fingerprint(r)
+    }
+    
+    d.mu.Lock()
+    entry, ok := d.seen[r.Fingerprint]
+    if ok && r.Time.Sub(entry.last) < d.window {
+        entry.suppressed++
+        d.mu.Unlock()
+        return
+    }
+    if ok {
+        r.Suppressed = entry.suppressed
+    }
+    d.forget(r.Time)
+    d.seen[r.Fingerprint] = &seen{last: r.Time}
+    d.mu.Unlock()
+    
+    select {
+    case d.queue <- r:
+    default:
+        d.logger.Warn("Error report queue full, dropping report", zap.String("fingerprint", r.Fingerprint))
+    }
+}
+
+// forget makes room once too many fingerprints are tracked. Entries outside
+// the window go first; if that frees nothing the oldest goes, and its
+// failure may be reported again within the window. Suppressed counts of
+// dropped entries are lost. Callers hold d.mu.
+func (d *Dispatcher) forget(now time.Time) {
+    if len(d.seen) < maxTracked {
+        return
+    }
+    var oldest string
+    var oldestLast time.Time
+    for fp, entry := range d.seen {
+        if now.Sub(entry.last) >= d.window {
+            delete(d.seen, fp)
+            continue
+        }
+        if oldestLast.IsZero() || entry.last.Before(oldestLast) {
+            oldest, oldestLast = fp, entry.last
+        }
+    }
+    if len(d.seen) >= maxTracked {
+        delete(d.seen, oldest)
+    }
+}
+
+// Run sends queued reports until ctx is cancelled, then sends what is
+// still queued.
+func (d *Dispatcher) Run(ctx context.Context) error {
+    for {
+        select {
+        case r := <-d.queue:
+            d.send(ctx, r)
+        case <-ctx.Done():
+            for {
+                select {
+                case r := <-d.queue:
+                    d.send(context.WithoutCancel(ctx), r)
+                default:
+                    return nil
+                }
+            }
+        }
+    }
+}
+
+func (d *Dispatcher) send(ctx context.Context, r Report) {
+    for _, sink := range d.sinks {
+        if err := sink.Send(ctx, r); err != nil {
+            d.logger.Warn("Failed to send error report",
+                zap.String("fingerprint", r.Fingerprint),
+                zap.Error(err),
+            )
+        }
+    }
+}
+
+var (
+    // goroutine headers, argument lists and pc offsets differ between
+    // occurrences of the same failure
+    goroutinePattern = regexp.MustCompile(`^goroutine \d+ `)
+    argsPattern      = regexp.MustCompile(`\(0x[0-9a-f, .]*\)$|\(\.\.\.\)$`)
+    offsetPattern    = regexp.MustCompile(` \+0x[0-9a-f]+$`)
+)
+
+// fingerprint hashes the frames of the stack, or the request's route,
+// status and message.
+func fingerprint(r Report) string {
+    var key strings.Builder
+    key.WriteString(string(r.Kind))
+    if r.Stack != "" {
+        for _, line := range strings.Split(r.Stack, "\n") {
+            line = strings.TrimSpace(line)
+            if line == "" || goroutinePattern.MatchString(line) {
+                continue
+            }
+            line = argsPattern.ReplaceAllString(line, "")
+            line = offsetPattern.ReplaceAllString(line, "")
+            key.WriteString("\n" + line)
+        }
+    } else {
+        key.WriteString("\n" + r.Method + " " + r.Route + "\n" + r.Message)
+    }
+    sum := sha256.Sum256([]byte(key.String()))
+    return hex.EncodeToString(sum[:8])
+}
+
+// TrimStack drops the frames of the recovery itself from a debug.Stack
+// taken in a deferred recover, so the trace starts at the call to panic.
+func TrimStack(stack []byte) string {
+    header, frames, ok := bytes.Cut(stack, []byte("\n"))
+    if !ok {
+        return string(stack)
+    }
+    if i := bytes.Index(frames, []byte("\npanic(")); i >= 0 {
+        frames = frames[i+1:]
+    }
+    return string(header) + "\n" + string(frames)
+}
@@ -0,0 +1,64 @@
+package errreport
+
+import (
+    "fmt"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+func TestReportDeduplicates(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    tests := []struct {
+        name       string
+        offsets    []time.Duration
+        wantQueued int
+        // wantSuppressed is the count carried by the last queued report
+        wantSuppressed int
+    }{
+        {"single", []time.Duration{0}, 1, 0},
+        {"duplicates within the window", []time.Duration{0, time.Second, 2 * time.Second}, 1, 0},
+        {"repeat after the window", []time.Duration{0, time.Second, time.Minute}, 2, 1},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            d := NewDispatcher([]Sink{nil}, time.Minute, 16, zap.NewNop())
+            for _, offset := range tt.offsets {
+                d.Report(Report{Kind: KindError, Time: start.Add(offset), Fingerprint: "fp"})
+            }
+            if len(d.queue) != tt.wantQueued {
+                t.Fatalf("queued %d reports, want %d", len(d.queue), tt.wantQueued)
+            }
+            var last Report
+            for len(d.queue) > 0 {
+                last = <-d.queue
+            }
+            if last.Suppressed != tt.wantSuppressed {
+                t.Errorf("suppressed = %d, want %d", last.Suppressed, tt.wantSuppressed)
+            }
+        })
+    }
+}
+
+func TestReportBoundsTrackedFingerprints(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    d := NewDispatcher([]Sink{nil}, time.Hour, 1, zap.NewNop())
+    
+    // All within one window, so none expire
+    for i := range maxTracked + 10 {
+        d.Report(Report{Kind: KindError, Time: start.Add(time.Duration(i) * time.Millisecond), Fingerprint: fmt.Sprint(i)})
+    }
+    if len(d.seen) > maxTracked {
+        t.Fatalf("tracking %d fingerprints, want at most %d", len(d.seen), maxTracked)
+    }
+    for i := range 10 {
+        if _, ok := d.seen[fmt.Sprint(i)]; ok {
+            t.Errorf("oldest fingerprint %d still tracked", i)
+        }
+    }
+    if _, ok := d.seen[fmt.Sprint(maxTracked+9)]; !ok {
+        t.Error("newest fingerprint not tracked")
+    }
+}
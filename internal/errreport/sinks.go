@@ -0,0 +1,154 @@
+package errreport
+
+import (
+    "bytes"
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "strconv"
+    "sync"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+// LogSink writes reports to the application log.
+type LogSink struct {
+    logger *zap.Logger
+}
+
+func NewLogSink(logger *zap.Logger) *LogSink {
+    return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Send(ctx context.Context, r Report) error {
+    s.logger.Error("Error report",
+        zap.String("kind", string(r.Kind)),
+        zap.String("message", r.Message),
+        zap.String("fingerprint", r.Fingerprint),
+        zap.Int("suppressed", r.Suppressed),
+        zap.String("request_id", r.RequestID),
+        zap.String("route", r.Route),
+        zap.Int("status", r.Status),
+    )
+    return nil
+}
+
+// FileSink appends reports to a file as JSON lines.
+type FileSink struct {
+    mu   sync.Mutex
+    file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+    if err != nil {
+        return nil, err
+    }
+    return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Send(ctx context.Context, r Report) error {
+    line, err := json.Marshal(r)
+    if err != nil {
+        return err
+    }
+    
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    _, err = s.file.Write(append(line, '\n'))
+    return err
+}
+
+func (s *FileSink) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.file.Close()
+}
+
+// HTTPSink posts reports as Sentry-style event JSON, for Sentry itself or
+// any collector that accepts the same shape.
+type HTTPSink struct {
+    url    string
+    client *http.Client
+}
+
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+    return &HTTPSink{
+        url:    url,
+        client: &http.Client{Timeout: timeout},
+    }
+}
+
+type httpEvent struct {
+    EventID     string            `json:"event_id"`
+    Timestamp   string            `json:"timestamp"`
+    Level       string            `json:"level"`
+    Platform    string            `json:"platform"`
+    Message     string            `json:"message"`
+    Fingerprint []string          `json:"fingerprint"`
+    Tags        map[string]string `json:"tags"`
+    Extra       map[string]any    `json:"extra"`
+}
+
+func (s *HTTPSink) Send(ctx context.Context, r Report) error {
+    level := "error"
+    if r.Kind == KindPanic {
+        level = "fatal"
+    }
+    event := httpEvent{
+        EventID:     eventID(),
+        Timestamp:   r.Time.UTC().Format(time.RFC3339Nano),
+        Level:       level,
+        Platform:    "go",
+        Message:     r.Message,
+        Fingerprint: []string{r.Fingerprint},
+        Tags: map[string]string{
+            "kind":       string(r.Kind),
+            "request_id": r.RequestID,
+            "trace_id":   r.TraceID,
+            "route":      r.Route,
+            "status":     strconv.Itoa(r.Status),
+        },
+        Extra: map[string]any{
+            "method":     r.Method,
+            "path":       r.Path,
+            "stack":      r.Stack,
+            "suppressed": r.Suppressed,
+        },
+    }
+    body, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    _, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+    
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("error report collector returned %s", resp.Status)
+    }
+    return nil
+}
+
+// eventID returns 32 hex digits, the event ID format Sentry expects.
+func eventID() string {
+    var b [16]byte
+    _, _ = rand.Read(b[:])
+    return hex.EncodeToString(b[:])
+}
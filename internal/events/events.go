@@ -0,0 +1,86 @@
+package events
+
+import (
+    "context"
+    "sync"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "go.uber.org/zap"
+)
+
+type Type string
+
+const (
+    UserCreated Type = "user.created"
+    UserUpdated Type = "user.updated"
+    UserDeleted Type = "user.deleted"
+)
+
+type Event struct {
+    // ID is the outbox sequence number, assigned once the event is relayed.
+    ID         int64                `json:"id,omitempty"`
+    Type       Type                 `json:"type"`
+    UserID     int32                `json:"user_id"`
+    User       *models.UserResponse `json:"user,omitempty"`
+    OccurredAt time.Time            `json:"occurred_at"`
+}
+
+type Publisher interface {
+    Publish(ctx context.Context, event Event) error
+}
+
+type Subscriber interface {
+    Subscribe(buffer int) (<-chan Event, func())
+}
+
+// Broker fans events out to in-process subscribers. Slow subscribers drop
+// events instead of blocking the publisher.
+type Broker struct {
+    mu          sync.RWMutex
+    subscribers map[chan Event]struct{}
+    logger      *zap.Logger
+}
+
+func NewBroker(logger *zap.Logger) *Broker {
+    return &Broker{
+        subscribers: make(map[chan Event]struct{}),
+        logger:      logger,
+    }
+}
+
+func (b *Broker) Publish(ctx context.Context, event Event) error {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    
+    for ch := range b.subscribers {
+        select {
+        case ch <- event:
+        default:
+            b.logger.Warn("Dropping event for slow subscriber",
+                zap.String("type", string(event.Type)),
+                zap.Int32("user_id", event.UserID),
+            )
+        }
+    }
+    return nil
+}
+
+func (b *Broker) Subscribe(buffer int) (<-chan Event, func()) {
+    ch := make(chan Event, buffer)
+    
+    b.mu.Lock()
+    b.subscribers[ch] = struct{}{}
+    b.mu.Unlock()
+    
+    var once sync.Once
+    unsubscribe := func() {
+        once.Do(func() {
+            b.mu.Lock()
+            delete(b.subscribers, ch)
+            b.mu.Unlock()
+            close(ch)
+        })
+    }
+    return ch, unsubscribe
+}
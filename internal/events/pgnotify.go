@@ -0,0 +1,47 @@
+package events
+
+import (
+    "context"
+    "encoding/json"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/pgnotify"
+)
+
+const NotifyChannel = "user_events"
+
+// NotifyPublisher broadcasts events to every replica through Postgres
+// NOTIFY. Each replica runs ListenAndPublish to feed its local Broker.
+type NotifyPublisher struct {
+    notifier *pgnotify.Notifier
+}
+
+func NewNotifyPublisher(notifier *pgnotify.Notifier) *NotifyPublisher {
+    return &NotifyPublisher{notifier: notifier}
+}
+
+func (p *NotifyPublisher) Publish(ctx context.Context, event Event) error {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    return p.notifier.Notify(ctx, NotifyChannel, string(payload))
+}
+
+// ListenAndPublish republishes events received on NotifyChannel to local
+// subscribers until ctx is cancelled.
+func ListenAndPublish(ctx context.Context, dsn string, local Publisher, logger *zap.Logger) error {
+    handle := func(payload string) {
+        var event Event
+        if err := json.Unmarshal([]byte(payload), &event); err != nil {
+            logger.Error("Failed to decode user event notification", zap.Error(err))
+            return
+        }
+        _ = local.Publish(ctx, event)
+    }
+    onReconnect := func() {
+        logger.Warn("User event listener reconnected, events may have been missed")
+    }
+    return pgnotify.Listen(ctx, dsn, NotifyChannel, handle, onReconnect, logger)
+}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+    "context"
+    "encoding/base64"
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+    
+    "github.com/99designs/gqlgen/graphql"
+    "github.com/99designs/gqlgen/graphql/handler"
+    "github.com/99designs/gqlgen/graphql/handler/extension"
+    "github.com/99designs/gqlgen/graphql/handler/transport"
+    "github.com/go-playground/validator/v10"
+    "github.com/vektah/gqlparser/v2/gqlerror"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/graph/generated"
+    ctxlog "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+)
+
+const (
+    defaultPageSize = 10
+    maxPageSize     = 100
+    cursorPrefix    = "user:"
+)
+
+var errInvalidID = errors.New("invalid user ID")
+
+// inputNames lists PII input fields by their schema names, which clients
+// use as keys in variables and differ from the models' JSON names, so
+// logged variables are masked too.
+type inputNames struct {
+    NameContains string `json:"nameContains" pii:"true"`
+}
+
+func init() {
+    redact.Register(inputNames{})
+}
+
+func NewHandler(userService service.UserService, maxDepth, maxComplexity int, logger *zap.Logger) http.Handler {
+    resolver := NewResolver(userService, logger)
+    srv := handler.New(generated.NewExecutableSchema(generated.Config{
+        Resolvers:  resolver,
+        Complexity: complexity(),
+    }))
+    
+    srv.AddTransport(transport.GET{})
+    srv.AddTransport(transport.POST{})
+    srv.Use(extension.Introspection{})
+    srv.Use(DepthLimit{Max: maxDepth})
+    srv.Use(extension.FixedComplexityLimit(maxComplexity))
+    
+    srv.SetErrorPresenter(presentError)
+    srv.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
+        ctxlog.FromContextOr(ctx, logger).Error("Panic recovered", zap.String("error", redact.Recovered(err)), zap.String("path", graphql.GetPath(ctx).String()))
+        return errors.New("Internal server error")
+    })
+    
+    return LoaderMiddleware(userService, srv)
+}
+
+// presentError maps service errors onto GraphQL errors with a stable
+// extensions.code, hiding unexpected errors from clients.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+    var gqlErr *gqlerror.Error
+    if errors.As(err, &gqlErr) && gqlErr.Extensions != nil {
+        return gqlErr
+    }
+    
+    presented := graphql.DefaultErrorPresenter(ctx, err)
+    switch {
+    case errors.Is(err, service.ErrUserNotFound):
+        presented.Message = "User not found"
+        presented.Extensions = map[string]interface{}{"code": "NOT_FOUND"}
+    case errors.Is(err, service.ErrInvalidDate), errors.Is(err, service.ErrFutureDOB), errors.Is(err, errInvalidID):
+        presented.Message = rootMessage(err)
+        presented.Extensions = map[string]interface{}{"code": "BAD_USER_INPUT"}
+    case errors.As(err, &gqlErr):
+        // Parser and validation errors raised by gqlgen itself
+    default:
+        presented.Message = "Internal server error"
+        presented.Extensions = map[string]interface{}{"code": "INTERNAL_SERVER_ERROR"}
+    }
+    return presented
+}
+
+func rootMessage(err error) string {
+    for _, target := range []error{service.ErrInvalidDate, service.ErrFutureDOB, errInvalidID} {
+        if errors.Is(err, target) {
+            return target.Error()
+        }
+    }
+    return err.Error()
+}
+
+func (r *Resolver) validate(input interface{}) error {
+    if err := r.validator.Struct(input); err != nil {
+        details := make(map[string]interface{})
+        for _, err := range err.(validator.ValidationErrors) {
+            details[err.Field()] = err.Tag()
+        }
+        return &gqlerror.Error{
+            Message: "Validation failed",
+            Extensions: map[string]interface{}{
+                "code":    "BAD_USER_INPUT",
+                "details": details,
+            },
+        }
+    }
+    return nil
+}
+
+func pageSize(first *int) int {
+    if first == nil || *first < 1 {
+        return defaultPageSize
+    }
+    if *first > maxPageSize {
+        return maxPageSize
+    }
+    return *first
+}
+
+func parseID(id string) (int32, error) {
+    value, err := strconv.ParseInt(id, 10, 32)
+    if err != nil {
+        return 0, errInvalidID
+    }
+    return int32(value), nil
+}
+
+func formatID(id int32) string {
+    return strconv.FormatInt(int64(id), 10)
+}
+
+func encodeCursor(id int32) string {
+    return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + formatID(id)))
+}
+
+func decodeCursor(cursor string) (int32, error) {
+    raw, err := base64.StdEncoding.DecodeString(cursor)
+    if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+        return 0, gqlerror.Errorf("invalid cursor")
+    }
+    return parseID(strings.TrimPrefix(string(raw), cursorPrefix))
+}
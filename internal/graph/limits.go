@@ -0,0 +1,72 @@
+package graph
+
+import (
+    "context"
+    "strings"
+    
+    "github.com/99designs/gqlgen/graphql"
+    "github.com/vektah/gqlparser/v2/ast"
+    "github.com/vektah/gqlparser/v2/gqlerror"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/graph/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+)
+
+// DepthLimit rejects operations whose selection sets nest deeper than Max.
+// Introspection fields are not counted.
+type DepthLimit struct {
+    Max int
+}
+
+var _ interface {
+    graphql.HandlerExtension
+    graphql.OperationContextMutator
+} = DepthLimit{}
+
+func (DepthLimit) ExtensionName() string {
+    return "DepthLimit"
+}
+
+func (DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+    return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+    if depth := selectionDepth(rc.Operation.SelectionSet); depth > d.Max {
+        return gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+    }
+    return nil
+}
+
+func selectionDepth(set ast.SelectionSet) int {
+    max := 0
+    for _, selection := range set {
+        depth := 0
+        switch s := selection.(type) {
+        case *ast.Field:
+            if strings.HasPrefix(s.Name, "__") {
+                continue
+            }
+            depth = 1 + selectionDepth(s.SelectionSet)
+        case *ast.InlineFragment:
+            depth = selectionDepth(s.SelectionSet)
+        case *ast.FragmentSpread:
+            if s.Definition != nil {
+                depth = selectionDepth(s.Definition.SelectionSet)
+            }
+        }
+        if depth > max {
+            max = depth
+        }
+    }
+    return max
+}
+
+// complexity weights list fields by the number of items they can return.
+func complexity() generated.ComplexityRoot {
+    var c generated.ComplexityRoot
+    c.Query.Users = func(childComplexity int, filter *models.UserFilter, first *int, after *string) int {
+        return 1 + pageSize(first)*childComplexity
+    }
+    return c
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    
+    "go.uber.org/zap"
+)
+
+func TestLimits(t *testing.T) {
+    handler := NewHandler(&fakeUserService{}, 3, 100, zap.NewNop())
+    
+    tests := []struct {
+        name    string
+        query   string
+        wantErr string
+    }{
+        {"shallow", `{ user(id: "1") { id name } }`, ""},
+        {"at the depth limit", `{ users(first: 1) { edges { cursor } } }`, ""},
+        {"too deep", `{ users(first: 1) { edges { node { id } } } }`, "depth 4, which exceeds the limit of 3"},
+        {"fragment spread", `{ ...Q } fragment Q on Query { users(first: 1) { edges { node { id } } } }`, "depth 4"},
+        {"inline fragment", `{ users(first: 1) { edges { node { ... on User { id } } } } }`, "depth 4"},
+        {"introspection is not counted", `{ __schema { types { fields { type { ofType { name } } } } } }`, ""},
+        {"within complexity", `{ users(first: 10) { edges { cursor } } }`, ""},
+        {"page size multiplies complexity", `{ users(first: 100) { edges { cursor } } }`, "complexity 201, which exceeds the limit of 100"},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            body, err := json.Marshal(map[string]string{"query": tt.query})
+            if err != nil {
+                t.Fatal(err)
+            }
+            req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+            req.Header.Set("Content-Type", "application/json")
+            rec := httptest.NewRecorder()
+            handler.ServeHTTP(rec, req)
+            
+            var response struct {
+                Errors []struct {
+                    Message string `json:"message"`
+                } `json:"errors"`
+            }
+            if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+                t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+            }
+            switch {
+            case tt.wantErr == "" && len(response.Errors) > 0:
+                t.Errorf("unexpected error: %s", response.Errors[0].Message)
+            case tt.wantErr != "" && (len(response.Errors) == 0 || !strings.Contains(response.Errors[0].Message, tt.wantErr)):
+                t.Errorf("errors = %+v, want one containing %q", response.Errors, tt.wantErr)
+            }
+        })
+    }
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+    "context"
+    "net/http"
+    "sync"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+)
+
+const (
+    loaderWait     = 2 * time.Millisecond
+    loaderMaxBatch = 100
+)
+
+type loaderKey struct{}
+
+// userLoader batches user lookups made while resolving a single request
+// into one GetUsersByIDs call, and caches the results for that request.
+type userLoader struct {
+    service service.UserService
+    
+    mu    sync.Mutex
+    cache map[int32]*models.UserResponse
+    batch *userBatch
+}
+
+type userBatch struct {
+    ids     []int32
+    results map[int32]*models.UserResponse
+    err     error
+    done    chan struct{}
+}
+
+func newUserLoader(service service.UserService) *userLoader {
+    return &userLoader{
+        service: service,
+        cache:   make(map[int32]*models.UserResponse),
+    }
+}
+
+// LoaderMiddleware attaches a fresh user loader to every request so cached
+// results never leak between requests.
+func LoaderMiddleware(service service.UserService, next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        ctx := context.WithValue(r.Context(), loaderKey{}, newUserLoader(service))
+        next.ServeHTTP(w, r.WithContext(ctx))
+    })
+}
+
+func loaderFor(ctx context.Context, service service.UserService) *userLoader {
+    if loader, ok := ctx.Value(loaderKey{}).(*userLoader); ok {
+        return loader
+    }
+    return newUserLoader(service)
+}
+
+// Load returns the user with the given ID, or nil if it does not exist.
+func (l *userLoader) Load(ctx context.Context, id int32) (*models.UserResponse, error) {
+    l.mu.Lock()
+    if user, ok := l.cache[id]; ok {
+        l.mu.Unlock()
+        return user, nil
+    }
+    
+    b := l.batch
+    if b == nil {
+        b = &userBatch{done: make(chan struct{})}
+        l.batch = b
+        time.AfterFunc(loaderWait, func() { l.dispatch(ctx, b) })
+    }
+    b.ids = append(b.ids, id)
+    if len(b.ids) >= loaderMaxBatch {
+        // Detach the full batch now so the next load starts another
+        l.batch = nil
+        go l.fetch(ctx, b)
+    }
+    l.mu.Unlock()
+    
+    select {
+    case <-b.done:
+    case <-ctx.Done():
+        return nil, ctx.Err()
+    }
+    if b.err != nil {
+        return nil, b.err
+    }
+    return b.results[id], nil
+}
+
+// Prime replaces the cached value for a user, or removes it when user is nil.
+func (l *userLoader) Prime(id int32, user *models.UserResponse) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    
+    if user == nil {
+        delete(l.cache, id)
+        return
+    }
+    l.cache[id] = user
+}
+
+// dispatch fetches b when its wait is over, unless it filled up first and
+// was already fetched.
+func (l *userLoader) dispatch(ctx context.Context, b *userBatch) {
+    l.mu.Lock()
+    if l.batch != b {
+        l.mu.Unlock()
+        return
+    }
+    l.batch = nil
+    l.mu.Unlock()
+    l.fetch(ctx, b)
+}
+
+func (l *userLoader) fetch(ctx context.Context, b *userBatch) {
+    users, err := l.service.GetUsersByIDs(ctx, b.ids)
+    if err != nil {
+        b.err = err
+        close(b.done)
+        return
+    }
+    
+    b.results = make(map[int32]*models.UserResponse, len(users))
+    l.mu.Lock()
+    for _, user := range users {
+        b.results[user.ID] = user
+        l.cache[user.ID] = user
+    }
+    l.mu.Unlock()
+    close(b.done)
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "sync"
+    "testing"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+)
+
+// fakeUserService has users 1 to 3 and records every batch lookup. Methods
+// the tests do not use panic through the nil embedded interface.
+type fakeUserService struct {
+    service.UserService
+    
+    mu      sync.Mutex
+    batches [][]int32
+    err     error
+}
+
+func (s *fakeUserService) GetUsersByIDs(ctx context.Context, ids []int32) ([]*models.UserResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.batches = append(s.batches, slices.Clone(ids))
+    if s.err != nil {
+        return nil, s.err
+    }
+    var users []*models.UserResponse
+    for _, id := range ids {
+        if id >= 1 && id <= 3 && !slices.ContainsFunc(users, func(u *models.UserResponse) bool { return u.ID == id }) {
+            users = append(users, &models.UserResponse{ID: id})
+        }
+    }
+    return users, nil
+}
+
+func (s *fakeUserService) SearchUsers(ctx context.Context, filter models.UserFilter, afterID int32, limit int) ([]*models.UserResponse, bool, error) {
+    return []*models.UserResponse{{ID: 1}}, false, nil
+}
+
+// loadAll loads ids concurrently and returns the users found, nil for
+// missing ones, with the first error.
+func loadAll(loader *userLoader, ids []int32) ([]*models.UserResponse, error) {
+    users := make([]*models.UserResponse, len(ids))
+    errs := make([]error, len(ids))
+    var wg sync.WaitGroup
+    for i, id := range ids {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            users[i], errs[i] = loader.Load(context.Background(), id)
+        }()
+    }
+    wg.Wait()
+    return users, errors.Join(errs...)
+}
+
+func TestUserLoader(t *testing.T) {
+    tests := []struct {
+        name string
+        // primed is cached before loading; a nil value evicts
+        primed      map[int32]*models.UserResponse
+        ids         []int32
+        err         error
+        wantFound   []bool
+        wantBatches int
+    }{
+        {name: "one batch for concurrent loads", ids: []int32{1, 2, 3, 1}, wantFound: []bool{true, true, true, true}, wantBatches: 1},
+        {name: "missing user is nil", ids: []int32{2, 9}, wantFound: []bool{true, false}, wantBatches: 1},
+        {name: "primed users are not fetched", primed: map[int32]*models.UserResponse{1: {ID: 1}, 2: {ID: 2}}, ids: []int32{1, 2}, wantFound: []bool{true, true}, wantBatches: 0},
+        {name: "priming nil evicts", primed: map[int32]*models.UserResponse{1: nil}, ids: []int32{1}, wantFound: []bool{true}, wantBatches: 1},
+        {name: "errors reach every caller", ids: []int32{1, 2}, err: errors.New("database down"), wantFound: []bool{false, false}, wantBatches: 1},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            service := &fakeUserService{err: tt.err}
+            loader := newUserLoader(service)
+            for id, user := range tt.primed {
+                loader.Prime(id, user)
+            }
+            
+            users, err := loadAll(loader, tt.ids)
+            if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+                t.Fatalf("error = %v, want %v", err, tt.err)
+            }
+            if len(service.batches) != tt.wantBatches {
+                t.Errorf("%d batches %v, want %d", len(service.batches), service.batches, tt.wantBatches)
+            }
+            for i, want := range tt.wantFound {
+                if found := users[i] != nil; found != want {
+                    t.Errorf("user %d found = %v, want %v", tt.ids[i], found, want)
+                } else if found && users[i].ID != tt.ids[i] {
+                    t.Errorf("Load(%d) returned user %d", tt.ids[i], users[i].ID)
+                }
+            }
+        })
+    }
+}
+
+func TestUserLoaderCaches(t *testing.T) {
+    service := &fakeUserService{}
+    loader := newUserLoader(service)
+    
+    for range 2 {
+        if _, err := loadAll(loader, []int32{1, 2}); err != nil {
+            t.Fatalf("load: %v", err)
+        }
+    }
+    if len(service.batches) != 1 {
+        t.Errorf("%d batches %v, want 1", len(service.batches), service.batches)
+    }
+}
+
+func TestUserLoaderSplitsLargeBatches(t *testing.T) {
+    service := &fakeUserService{}
+    ids := make([]int32, 2*loaderMaxBatch+10)
+    for i := range ids {
+        ids[i] = int32(i + 1)
+    }
+    if _, err := loadAll(newUserLoader(service), ids); err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    
+    fetched := 0
+    for _, batch := range service.batches {
+        if len(batch) > loaderMaxBatch {
+            t.Errorf("batch of %d IDs, want at most %d", len(batch), loaderMaxBatch)
+        }
+        fetched += len(batch)
+    }
+    if fetched != len(ids) {
+        t.Errorf("fetched %d IDs, want %d", fetched, len(ids))
+    }
+}
@@ -0,0 +1,24 @@
+package graph
+
+import (
+    "github.com/go-playground/validator/v10"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+)
+
+// Resolver is the root GraphQL resolver. It is backed by the same
+// service.UserService as the HTTP and gRPC APIs.
+type Resolver struct {
+    service   service.UserService
+    validator *validator.Validate
+    logger    *zap.Logger
+}
+
+func NewResolver(service service.UserService, logger *zap.Logger) *Resolver {
+    return &Resolver{
+        service:   service,
+        validator: validator.New(),
+        logger:    logger,
+    }
+}
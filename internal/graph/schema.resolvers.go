@@ -0,0 +1,149 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adityaK87/go-backend-assignment/internal/graph/generated"
+	"github.com/adityaK87/go-backend-assignment/internal/graph/model"
+	"github.com/adityaK87/go-backend-assignment/internal/models"
+	"github.com/adityaK87/go-backend-assignment/internal/service"
+)
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input models.CreateUserRequest) (*models.UserResponse, error) {
+	if err := r.validate(input); err != nil {
+		return nil, err
+	}
+
+	user, err := r.service.CreateUser(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	loaderFor(ctx, r.service).Prime(user.ID, user)
+	return user, nil
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input models.UpdateUserRequest) (*models.UserResponse, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validate(input); err != nil {
+		return nil, err
+	}
+
+	user, err := r.service.UpdateUser(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	loaderFor(ctx, r.service).Prime(userID, user)
+	return user, nil
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteUser(ctx, userID); err != nil {
+		return false, err
+	}
+
+	loaderFor(ctx, r.service).Prime(userID, nil)
+	return true, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*models.UserResponse, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return loaderFor(ctx, r.service).Load(ctx, userID)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, filter *models.UserFilter, first *int, after *string) (*model.UserConnection, error) {
+	var afterID int32
+	if after != nil {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		afterID = id
+	}
+
+	var userFilter models.UserFilter
+	if filter != nil {
+		if err := r.validate(filter); err != nil {
+			return nil, err
+		}
+		userFilter = *filter
+	}
+
+	users, hasMore, err := r.service.SearchUsers(ctx, userFilter, afterID, pageSize(first))
+	if err != nil {
+		return nil, err
+	}
+
+	loader := loaderFor(ctx, r.service)
+	connection := &model.UserConnection{
+		Edges:    make([]*model.UserEdge, len(users)),
+		PageInfo: &model.PageInfo{HasNextPage: hasMore},
+	}
+	for i, user := range users {
+		loader.Prime(user.ID, user)
+		connection.Edges[i] = &model.UserEdge{
+			Cursor: encodeCursor(user.ID),
+			Node:   user,
+		}
+	}
+	if len(users) > 0 {
+		endCursor := connection.Edges[len(users)-1].Cursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+	return connection, nil
+}
+
+// ID is the resolver for the id field.
+func (r *userResolver) ID(ctx context.Context, obj *models.UserResponse) (string, error) {
+	return formatID(obj.ID), nil
+}
+
+// Age is the resolver for the age field.
+func (r *userResolver) Age(ctx context.Context, obj *models.UserResponse) (int, error) {
+	if obj.Age != nil {
+		return *obj.Age, nil
+	}
+
+	dob, err := time.Parse("2006-01-02", obj.DOB)
+	if err != nil {
+		return 0, errors.Join(service.ErrInvalidDate, err)
+	}
+	return models.CalculateAge(dob), nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,183 @@
+package grpcapi
+
+import (
+    "context"
+    "errors"
+    "runtime/debug"
+    "time"
+    
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    ctxlog "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+    "github.com/adityaK87/go-backend-assignment/internal/traceid"
+)
+
+const requestIDHeader = "x-request-id"
+
+// wrappedStream lets stream interceptors replace the stream context.
+type wrappedStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+    return w.ctx
+}
+
+// withRequestID tags the call with a request ID and trace ID, taken from the
+// incoming metadata when present, and a logger carrying both plus the method.
+func withRequestID(ctx context.Context, logger *zap.Logger, method string) context.Context {
+    // Get request ID from metadata or generate new one
+    var requestID string
+    if md, ok := metadata.FromIncomingContext(ctx); ok {
+        if values := md.Get(requestIDHeader); len(values) > 0 {
+            requestID = values[0]
+        }
+    }
+    if requestID == "" {
+        requestID = uuid.New().String()
+    }
+    
+    var traceparent string
+    if md, ok := metadata.FromIncomingContext(ctx); ok {
+        if values := md.Get(traceid.Header); len(values) > 0 {
+            traceparent = values[0]
+        }
+    }
+    traceID := traceid.FromHeader(traceparent)
+    
+    _ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+    ctx = requestid.NewContext(ctx, requestID)
+    ctx = traceid.NewContext(ctx, traceID)
+    return ctxlog.NewContext(ctx, logger.With(
+        zap.String("request_id", requestID),
+        zap.String("trace_id", traceID),
+        zap.String("route", method),
+    ))
+}
+
+func RequestIDUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        return handler(withRequestID(ctx, logger, info.FullMethod), req)
+    }
+}
+
+func RequestIDStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+        return handler(srv, &wrappedStream{ServerStream: ss, ctx: withRequestID(ss.Context(), logger, info.FullMethod)})
+    }
+}
+
+// logCall and recovered rely on the request logger for the method name,
+// which RequestID*Interceptor adds as "route".
+func logCall(logger *zap.Logger, ctx context.Context, start time.Time, err error) {
+    ctxlog.FromContextOr(ctx, logger).Info("gRPC Request",
+        zap.String("code", status.Code(err).String()),
+        zap.Duration("duration", time.Since(start)),
+    )
+}
+
+func LoggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        start := time.Now()
+        resp, err := handler(ctx, req)
+        logCall(logger, ctx, start, err)
+        return resp, err
+    }
+}
+
+func LoggingStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+        start := time.Now()
+        err := handler(srv, ss)
+        logCall(logger, ss.Context(), start, err)
+        return err
+    }
+}
+
+// recovered logs a panic with its stack and sends it to reporter, as the
+// HTTP Recover middleware does.
+func recovered(logger *zap.Logger, reporter *errreport.Dispatcher, ctx context.Context, method string, r interface{}, stack string) error {
+    message := redact.Recovered(r)
+    ctxlog.FromContextOr(ctx, logger).Error("Panic recovered",
+        zap.String("error", message),
+        zap.String("stack", stack),
+    )
+    reporter.Report(errreport.Report{
+        Kind:      errreport.KindPanic,
+        Message:   message,
+        Stack:     stack,
+        RequestID: requestid.FromContext(ctx),
+        TraceID:   traceid.FromContext(ctx),
+        Route:     method,
+    })
+    return status.Error(codes.Internal, "Internal server error")
+}
+
+func RecoveryUnaryInterceptor(logger *zap.Logger, reporter *errreport.Dispatcher) grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+        defer func() {
+            if r := recover(); r != nil {
+                err = recovered(logger, reporter, ctx, info.FullMethod, r, errreport.TrimStack(debug.Stack()))
+            }
+        }()
+        return handler(ctx, req)
+    }
+}
+
+func RecoveryStreamInterceptor(logger *zap.Logger, reporter *errreport.Dispatcher) grpc.StreamServerInterceptor {
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+        defer func() {
+            if r := recover(); r != nil {
+                err = recovered(logger, reporter, ss.Context(), info.FullMethod, r, errreport.TrimStack(debug.Stack()))
+            }
+        }()
+        return handler(srv, ss)
+    }
+}
+
+// toStatus maps service and context errors to gRPC status codes. Unknown
+// errors become Internal without leaking their message to the client.
+func toStatus(err error) error {
+    if err == nil {
+        return nil
+    }
+    if _, ok := status.FromError(err); ok {
+        return err
+    }
+    
+    switch {
+    case errors.Is(err, service.ErrUserNotFound):
+        return status.Error(codes.NotFound, "User not found")
+    case errors.Is(err, service.ErrInvalidDate), errors.Is(err, service.ErrFutureDOB):
+        return status.Error(codes.InvalidArgument, err.Error())
+    case errors.Is(err, context.Canceled):
+        return status.Error(codes.Canceled, err.Error())
+    case errors.Is(err, context.DeadlineExceeded):
+        return status.Error(codes.DeadlineExceeded, err.Error())
+    default:
+        return status.Error(codes.Internal, "Internal server error")
+    }
+}
+
+func ErrorUnaryInterceptor() grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+        resp, err := handler(ctx, req)
+        return resp, toStatus(err)
+    }
+}
+
+func ErrorStreamInterceptor() grpc.StreamServerInterceptor {
+    return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+        return toStatus(handler(srv, ss))
+    }
+}
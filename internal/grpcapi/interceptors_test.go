@@ -0,0 +1,94 @@
+package grpcapi
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+)
+
+type recordingSink struct {
+    reports []errreport.Report
+}
+
+func (s *recordingSink) Send(ctx context.Context, r errreport.Report) error {
+    s.reports = append(s.reports, r)
+    return nil
+}
+
+type panickingStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s panickingStream) Context() context.Context {
+    return s.ctx
+}
+
+func TestRecoveryReportsPanics(t *testing.T) {
+    const method = "/users.v1.UserService/GetUser"
+    
+    tests := []struct {
+        name string
+        call func(ctx context.Context, reporter *errreport.Dispatcher) error
+    }{
+        {
+            name: "unary",
+            call: func(ctx context.Context, reporter *errreport.Dispatcher) error {
+                _, err := RecoveryUnaryInterceptor(zap.NewNop(), reporter)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+                    func(ctx context.Context, req interface{}) (interface{}, error) {
+                        panic(`lookup of "Jane Doe" failed`)
+                    })
+                return err
+            },
+        },
+        {
+            name: "stream",
+            call: func(ctx context.Context, reporter *errreport.Dispatcher) error {
+                return RecoveryStreamInterceptor(zap.NewNop(), reporter)(nil, panickingStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method},
+                    func(srv interface{}, ss grpc.ServerStream) error {
+                        panic(`lookup of "Jane Doe" failed`)
+                    })
+            },
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            sink := &recordingSink{}
+            reporter := errreport.NewDispatcher([]errreport.Sink{sink}, time.Minute, 10, zap.NewNop())
+            
+            err := tt.call(requestid.NewContext(context.Background(), "req-1"), reporter)
+            if status.Code(err) != codes.Internal {
+                t.Errorf("code = %v, want Internal", status.Code(err))
+            }
+            
+            // A cancelled Run sends what is queued and returns
+            ctx, cancel := context.WithCancel(context.Background())
+            cancel()
+            _ = reporter.Run(ctx)
+            
+            if len(sink.reports) != 1 {
+                t.Fatalf("got %d reports, want 1", len(sink.reports))
+            }
+            r := sink.reports[0]
+            if r.Kind != errreport.KindPanic || r.Route != method || r.RequestID != "req-1" {
+                t.Errorf("report = %+v", r)
+            }
+            if strings.Contains(r.Message, "Jane Doe") {
+                t.Errorf("message was not redacted: %q", r.Message)
+            }
+            if !strings.HasPrefix(r.Stack, "goroutine ") || !strings.Contains(r.Stack, "panic(") {
+                t.Errorf("stack = %q", r.Stack)
+            }
+        })
+    }
+}
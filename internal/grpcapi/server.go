@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+    "context"
+    
+    "go.uber.org/zap"
+    "google.golang.org/grpc"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    usersv1 "github.com/adityaK87/go-backend-assignment/proto/users/v1"
+)
+
+func NewServer(userServer *UserServer, logger *zap.Logger, reporter *errreport.Dispatcher) *grpc.Server {
+    server := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(
+            RequestIDUnaryInterceptor(logger),
+            LoggingUnaryInterceptor(logger),
+            ErrorUnaryInterceptor(),
+            RecoveryUnaryInterceptor(logger, reporter),
+        ),
+        grpc.ChainStreamInterceptor(
+            RequestIDStreamInterceptor(logger),
+            LoggingStreamInterceptor(logger),
+            ErrorStreamInterceptor(),
+            RecoveryStreamInterceptor(logger, reporter),
+        ),
+    )
+    usersv1.RegisterUserServiceServer(server, userServer)
+    return server
+}
+
+// Shutdown stops the server gracefully, forcing long-lived streams such as
+// WatchUsers closed once ctx is done.
+func Shutdown(ctx context.Context, server *grpc.Server) error {
+    stopped := make(chan struct{})
+    go func() {
+        server.GracefulStop()
+        close(stopped)
+    }()
+    
+    select {
+    case <-stopped:
+        return nil
+    case <-ctx.Done():
+        server.Stop()
+        return ctx.Err()
+    }
+}
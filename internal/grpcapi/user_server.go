@@ -0,0 +1,193 @@
+package grpcapi
+
+import (
+    "context"
+    
+    "github.com/go-playground/validator/v10"
+    "go.uber.org/zap"
+    "google.golang.org/genproto/googleapis/rpc/errdetails"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+    usersv1 "github.com/adityaK87/go-backend-assignment/proto/users/v1"
+)
+
+const watchBuffer = 64
+
+type UserServer struct {
+    usersv1.UnimplementedUserServiceServer
+    
+    service    service.UserService
+    subscriber events.Subscriber
+    validator  *validator.Validate
+    logger     *zap.Logger
+}
+
+func NewUserServer(service service.UserService, subscriber events.Subscriber, logger *zap.Logger) *UserServer {
+    return &UserServer{
+        service:    service,
+        subscriber: subscriber,
+        validator:  validator.New(),
+        logger:     logger,
+    }
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *usersv1.CreateUserRequest) (*usersv1.CreateUserResponse, error) {
+    input := models.CreateUserRequest{
+        Name: req.GetName(),
+        DOB:  req.GetDob(),
+    }
+    if err := s.validate(input); err != nil {
+        return nil, err
+    }
+    
+    user, err := s.service.CreateUser(ctx, input)
+    if err != nil {
+        return nil, err
+    }
+    
+    return &usersv1.CreateUserResponse{User: toProtoUser(user)}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.GetUserResponse, error) {
+    user, err := s.service.GetUserByID(ctx, req.GetId())
+    if err != nil {
+        return nil, err
+    }
+    
+    return &usersv1.GetUserResponse{User: toProtoUser(user)}, nil
+}
+
+func (s *UserServer) ListUsers(req *usersv1.ListUsersRequest, stream grpc.ServerStreamingServer[usersv1.ListUsersResponse]) error {
+    page := int(req.GetPage())
+    if page == 0 {
+        page = 1
+    }
+    limit := int(req.GetLimit())
+    if limit == 0 {
+        limit = 10
+    }
+    
+    users, err := s.service.ListUsers(stream.Context(), page, limit)
+    if err != nil {
+        return err
+    }
+    
+    for _, user := range users {
+        if err := stream.Send(&usersv1.ListUsersResponse{User: toProtoUser(user)}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*usersv1.UpdateUserResponse, error) {
+    input := models.UpdateUserRequest{
+        Name: req.GetName(),
+        DOB:  req.GetDob(),
+    }
+    if err := s.validate(input); err != nil {
+        return nil, err
+    }
+    
+    user, err := s.service.UpdateUser(ctx, req.GetId(), input)
+    if err != nil {
+        return nil, err
+    }
+    
+    return &usersv1.UpdateUserResponse{User: toProtoUser(user)}, nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *usersv1.DeleteUserRequest) (*usersv1.DeleteUserResponse, error) {
+    if err := s.service.DeleteUser(ctx, req.GetId()); err != nil {
+        return nil, err
+    }
+    
+    return &usersv1.DeleteUserResponse{}, nil
+}
+
+func (s *UserServer) WatchUsers(req *usersv1.WatchUsersRequest, stream grpc.ServerStreamingServer[usersv1.WatchUsersResponse]) error {
+    filter := make(map[int32]struct{}, len(req.GetUserIds()))
+    for _, id := range req.GetUserIds() {
+        filter[id] = struct{}{}
+    }
+    
+    ch, unsubscribe := s.subscriber.Subscribe(watchBuffer)
+    defer unsubscribe()
+    
+    for {
+        select {
+        case <-stream.Context().Done():
+            return nil
+        case event, ok := <-ch:
+            if !ok {
+                return nil
+            }
+            if _, wanted := filter[event.UserID]; len(filter) > 0 && !wanted {
+                continue
+            }
+            if err := stream.Send(toProtoEvent(event)); err != nil {
+                return err
+            }
+        }
+    }
+}
+
+func (s *UserServer) validate(req interface{}) error {
+    if err := s.validator.Struct(req); err != nil {
+        details := &errdetails.BadRequest{}
+        for _, err := range err.(validator.ValidationErrors) {
+            details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+                Field:       err.Field(),
+                Description: err.Tag(),
+            })
+        }
+        st, detailErr := status.New(codes.InvalidArgument, "Validation failed").WithDetails(details)
+        if detailErr != nil {
+            return status.Error(codes.InvalidArgument, "Validation failed")
+        }
+        return st.Err()
+    }
+    return nil
+}
+
+func toProtoUser(user *models.UserResponse) *usersv1.User {
+    if user == nil {
+        return nil
+    }
+    
+    out := &usersv1.User{
+        Id:   user.ID,
+        Name: user.Name,
+        Dob:  user.DOB,
+    }
+    if user.Age != nil {
+        age := int32(*user.Age)
+        out.Age = &age
+    }
+    return out
+}
+
+func toProtoEvent(event events.Event) *usersv1.WatchUsersResponse {
+    var eventType usersv1.EventType
+    switch event.Type {
+    case events.UserCreated:
+        eventType = usersv1.EventType_EVENT_TYPE_CREATED
+    case events.UserUpdated:
+        eventType = usersv1.EventType_EVENT_TYPE_UPDATED
+    case events.UserDeleted:
+        eventType = usersv1.EventType_EVENT_TYPE_DELETED
+    }
+    
+    return &usersv1.WatchUsersResponse{
+        Type:       eventType,
+        UserId:     event.UserID,
+        User:       toProtoUser(event.User),
+        OccurredAt: timestamppb.New(event.OccurredAt),
+    }
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+    "runtime/pprof"
+    
+    "github.com/gofiber/fiber/v2"
+)
+
+// DiagnosticsHandler serves runtime state that the pprof endpoints do not
+// show in a readable form.
+type DiagnosticsHandler struct{}
+
+func NewDiagnosticsHandler() *DiagnosticsHandler {
+    return &DiagnosticsHandler{}
+}
+
+// Goroutines writes the stack of every goroutine as plain text, in the
+// format of a crash dump.
+func (h *DiagnosticsHandler) Goroutines(c *fiber.Ctx) error {
+    c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+    return pprof.Lookup("goroutine").WriteTo(c, 2)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+    "github.com/gofiber/fiber/v2"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+)
+
+// ErrorHandler answers errors returned through the middleware chain, adding
+// the request ID to server errors so they can be traced in the logs.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+    code := fiber.StatusInternalServerError
+    if e, ok := err.(*fiber.Error); ok {
+        code = e.Code
+    }
+    body := fiber.Map{
+        "error": err.Error(),
+    }
+    if code >= fiber.StatusInternalServerError {
+        body["request_id"] = requestid.FromContext(c.UserContext())
+    }
+    return c.Status(code).JSON(body)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+    "github.com/gofiber/fiber/v2"
+    "github.com/adityaK87/go-backend-assignment/internal/health"
+)
+
+type HealthHandler struct {
+    registry *health.Registry
+    detail   bool
+}
+
+// NewHealthHandler answers probes from registry. With detail, ?verbose
+// responses carry each check's error and duration, which can name hosts
+// and accounts, so only the admin listener enables it; otherwise they
+// carry just each check's status.
+func NewHealthHandler(registry *health.Registry, detail bool) *HealthHandler {
+    return &HealthHandler{
+        registry: registry,
+        detail:   detail,
+    }
+}
+
+// checkStatus is the public form of a health.CheckResult.
+type checkStatus struct {
+    Name    string `json:"name"`
+    Healthy bool   `json:"healthy"`
+}
+
+func (h *HealthHandler) Livez(c *fiber.Ctx) error {
+    return h.respond(c, health.Liveness)
+}
+
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+    return h.respond(c, health.Readiness)
+}
+
+func (h *HealthHandler) Startupz(c *fiber.Ctx) error {
+    return h.respond(c, health.Startup)
+}
+
+// respond answers 200 or 503. With ?verbose the result of every check is
+// included, in full only when the handler was built with detail.
+func (h *HealthHandler) respond(c *fiber.Ctx, probe health.Probe) error {
+    report := h.registry.Run(c.UserContext(), probe)
+    
+    code := fiber.StatusOK
+    body := fiber.Map{"status": "ok"}
+    if !report.Healthy {
+        code = fiber.StatusServiceUnavailable
+        body["status"] = "unavailable"
+    }
+    if c.Request().URI().QueryArgs().Has("verbose") {
+        if h.detail {
+            body["checks"] = report.Checks
+        } else {
+            checks := make([]checkStatus, len(report.Checks))
+            for i, check := range report.Checks {
+                checks[i] = checkStatus{Name: check.Name, Healthy: check.Healthy}
+            }
+            body["checks"] = checks
+        }
+    }
+    
+    c.Set(fiber.HeaderCacheControl, "no-store")
+    return c.Status(code).JSON(body)
+}
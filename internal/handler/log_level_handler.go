@@ -0,0 +1,81 @@
+package handler
+
+import (
+    "github.com/go-playground/validator/v10"
+    "github.com/gofiber/fiber/v2"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+)
+
+type LogLevelHandler struct {
+    validator *validator.Validate
+}
+
+func NewLogLevelHandler() *LogLevelHandler {
+    return &LogLevelHandler{
+        validator: validator.New(),
+    }
+}
+
+func (h *LogLevelHandler) GetLevel(c *fiber.Ctx) error {
+    return c.JSON(currentLogLevel())
+}
+
+func (h *LogLevelHandler) SetLevel(c *fiber.Ctx) error {
+    var req models.LogLevelRequest
+    
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Invalid request body",
+        })
+    }
+    
+    if err := h.validator.Struct(req); err != nil {
+        details := make(map[string]string)
+        for _, err := range err.(validator.ValidationErrors) {
+            details[err.Field()] = err.Tag()
+        }
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error:   "Validation failed",
+            Details: details,
+        })
+    }
+    if req.Level == "" && req.Packages == nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Either level or packages is required",
+        })
+    }
+    
+    if req.Packages != nil {
+        entries := make([]string, 0, len(req.Packages))
+        for pkg, level := range req.Packages {
+            entries = append(entries, pkg+"="+level)
+        }
+        if err := logger.SetPackageLevels(entries); err != nil {
+            return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+                Error: err.Error(),
+            })
+        }
+    }
+    if req.Level != "" {
+        // Already validated, so this cannot fail
+        level, _ := zapcore.ParseLevel(req.Level)
+        logger.SetLevel(level)
+    }
+    
+    response := currentLogLevel()
+    logger.FromContext(c.UserContext()).Warn("Log level changed",
+        zap.String("level", response.Level),
+        zap.Any("packages", response.Packages),
+    )
+    return c.JSON(response)
+}
+
+func currentLogLevel() models.LogLevelResponse {
+    return models.LogLevelResponse{
+        Level:    logger.Level().String(),
+        Packages: logger.PackageLevels(),
+    }
+}
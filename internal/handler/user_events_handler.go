@@ -0,0 +1,114 @@
+package handler
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+    
+    "github.com/gofiber/fiber/v2"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/sse"
+    "go.uber.org/zap"
+)
+
+const sseRetry = 3 * time.Second
+
+type UserEventsHandler struct {
+    hub       *sse.Hub
+    heartbeat time.Duration
+    logger    *zap.Logger
+}
+
+func NewUserEventsHandler(hub *sse.Hub, heartbeat time.Duration, logger *zap.Logger) *UserEventsHandler {
+    return &UserEventsHandler{
+        hub:       hub,
+        heartbeat: heartbeat,
+        logger:    logger,
+    }
+}
+
+// Stream serves user events as Server-Sent Events. Clients resume with the
+// Last-Event-ID header (or last_event_id query parameter, for the first
+// EventSource connection) and can filter with user_id=1,2,3.
+func (h *UserEventsHandler) Stream(c *fiber.Ctx) error {
+    lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+    var lastID int64
+    if lastEventID != "" {
+        id, err := strconv.ParseInt(lastEventID, 10, 64)
+        if err != nil {
+            return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+                Error: "Invalid Last-Event-ID",
+            })
+        }
+        lastID = id
+    }
+    
+    var userIDs []int32
+    if raw := c.Query("user_id"); raw != "" {
+        for _, part := range strings.Split(raw, ",") {
+            id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+            if err != nil {
+                return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+                    Error: "Invalid user ID",
+                })
+            }
+            userIDs = append(userIDs, int32(id))
+        }
+    }
+    
+    replay, ch, unsubscribe := h.hub.Subscribe(lastID, userIDs)
+    
+    c.Set("Content-Type", "text/event-stream")
+    c.Set("Cache-Control", "no-store")
+    c.Set("Connection", "keep-alive")
+    c.Set("X-Accel-Buffering", "no")
+    
+    heartbeat := h.heartbeat
+    c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+        defer unsubscribe()
+        
+        fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+        for _, event := range replay {
+            writeEvent(w, event)
+        }
+        if err := w.Flush(); err != nil {
+            return
+        }
+        
+        ticker := time.NewTicker(heartbeat)
+        defer ticker.Stop()
+        
+        for {
+            select {
+            case event, ok := <-ch:
+                if !ok {
+                    return
+                }
+                writeEvent(w, event)
+            case <-ticker.C:
+                w.WriteString(": heartbeat\n\n")
+            }
+            // A failed flush means the client has gone away
+            if err := w.Flush(); err != nil {
+                return
+            }
+        }
+    })
+    
+    return nil
+}
+
+func writeEvent(w *bufio.Writer, event events.Event) {
+    data, err := json.Marshal(event)
+    if err != nil {
+        return
+    }
+    
+    fmt.Fprintf(w, "id: %d\n", event.ID)
+    fmt.Fprintf(w, "event: %s\n", event.Type)
+    fmt.Fprintf(w, "data: %s\n\n", data)
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+    "errors"
     "strconv"
     
     "github.com/go-playground/validator/v10"
     "github.com/gofiber/fiber/v2"
     "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
     "github.com/adityaK87/go-backend-assignment/internal/service"
     "go.uber.org/zap"
 )
@@ -44,10 +46,10 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
         })
     }
     
-    user, err := h.service.CreateUser(c.Context(), req)
+    user, err := h.service.CreateUser(c.UserContext(), req)
     if err != nil {
         return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-            Error: err.Error(),
+            Error: redact.Error(err),
         })
     }
     
@@ -62,15 +64,15 @@ func (h *UserHandler) GetUser(c *fiber.Ctx) error {
         })
     }
     
-    user, err := h.service.GetUserByID(c.Context(), int32(id))
+    user, err := h.service.GetUserByID(c.UserContext(), int32(id))
     if err != nil {
-        if err.Error() == "user not found" {
+        if errors.Is(err, service.ErrUserNotFound) {
             return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
                 Error: "User not found",
             })
         }
         return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-            Error: err.Error(),
+            Error: redact.Error(err),
         })
     }
     
@@ -93,10 +95,10 @@ func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
         pagination.Limit = 10
     }
     
-    users, err := h.service.ListUsers(c.Context(), pagination.Page, pagination.Limit)
+    users, err := h.service.ListUsers(c.UserContext(), pagination.Page, pagination.Limit)
     if err != nil {
         return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-            Error: err.Error(),
+            Error: redact.Error(err),
         })
     }
     
@@ -130,15 +132,15 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
         })
     }
     
-    user, err := h.service.UpdateUser(c.Context(), int32(id), req)
+    user, err := h.service.UpdateUser(c.UserContext(), int32(id), req)
     if err != nil {
-        if err.Error() == "user not found" {
+        if errors.Is(err, service.ErrUserNotFound) {
             return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
                 Error: "User not found",
             })
         }
         return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-            Error: err.Error(),
+            Error: redact.Error(err),
         })
     }
     
@@ -153,14 +155,14 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
         })
     }
     
-    if err := h.service.DeleteUser(c.Context(), int32(id)); err != nil {
-        if err.Error() == "user not found" {
+    if err := h.service.DeleteUser(c.UserContext(), int32(id)); err != nil {
+        if errors.Is(err, service.ErrUserNotFound) {
             return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
                 Error: "User not found",
             })
         }
         return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-            Error: err.Error(),
+            Error: redact.Error(err),
         })
     }
     
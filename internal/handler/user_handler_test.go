@@ -0,0 +1,238 @@
+package handler_test
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "io"
+    "net/http/httptest"
+    "net/url"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+    
+    "github.com/gofiber/fiber/v2"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/accesslog"
+    "github.com/adityaK87/go-backend-assignment/internal/auth"
+    "github.com/adityaK87/go-backend-assignment/internal/clientip"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/graph"
+    "github.com/adityaK87/go-backend-assignment/internal/handler"
+    "github.com/adityaK87/go-backend-assignment/internal/health"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/middleware"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+    "github.com/adityaK87/go-backend-assignment/internal/routes"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+    "github.com/adityaK87/go-backend-assignment/internal/sse"
+)
+
+const (
+    seededName = "Zelda Quixote"
+    seededDOB  = "1987-06-05"
+    otherName  = "Yorick Abernathy"
+    otherDOB   = "1991-02-03"
+    
+    // panicID is the user whose lookup panics
+    panicID = 999
+)
+
+// faultyRepository fails creating users named seededName the way database
+// drivers do, with the offending values quoted in the error, and panics
+// looking up panicID with the seeded user in the panic value.
+type faultyRepository struct {
+    repository.UserRepository
+}
+
+func (r faultyRepository) Create(ctx context.Context, name string, dob time.Time) (*db.User, error) {
+    if name == seededName {
+        return nil, fmt.Errorf(`constraint failed: name "%s" with dob %s`, name, dob.Format("2006-01-02"))
+    }
+    return r.UserRepository.Create(ctx, name, dob)
+}
+
+func (r faultyRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
+    if id == panicID {
+        panic(fmt.Sprintf(`corrupt row for "%s" born %s`, seededName, seededDOB))
+    }
+    return r.UserRepository.GetByID(ctx, id)
+}
+
+// TestUserRoutesDoNotLogPII sends requests through the production
+// middleware chain and routes, with request and response bodies captured,
+// and checks that neither the access log nor the application log holds a
+// name or date of birth.
+func TestUserRoutesDoNotLogPII(t *testing.T) {
+    graphqlGet := func(query, variables string) string {
+        values := url.Values{"query": {query}}
+        if variables != "" {
+            values.Set("variables", variables)
+        }
+        return "/graphql?" + values.Encode()
+    }
+    userJSON := func(name, dob string) string {
+        return `{"name":"` + name + `","dob":"` + dob + `"}`
+    }
+    
+    requests := []struct {
+        name   string
+        method string
+        path   string
+        body   string
+        status int
+    }{
+        {"create", fiber.MethodPost, "/users", userJSON(otherName, otherDOB), fiber.StatusCreated},
+        {"create failing", fiber.MethodPost, "/users", userJSON(seededName, seededDOB), fiber.StatusInternalServerError},
+        {"create invalid", fiber.MethodPost, "/users", userJSON(otherName, "05/06/1987"), fiber.StatusBadRequest},
+        {"get", fiber.MethodGet, "/users/1", "", fiber.StatusOK},
+        {"get panicking", fiber.MethodGet, fmt.Sprintf("/users/%d", panicID), "", fiber.StatusInternalServerError},
+        {"list", fiber.MethodGet, "/users?page=1&limit=10", "", fiber.StatusOK},
+        {"update", fiber.MethodPut, "/users/1", userJSON(seededName, seededDOB), fiber.StatusOK},
+        {"update missing", fiber.MethodPut, "/users/404", userJSON(otherName, otherDOB), fiber.StatusNotFound},
+        {"update panicking", fiber.MethodPut, fmt.Sprintf("/users/%d", panicID), userJSON(otherName, otherDOB), fiber.StatusInternalServerError},
+        {"GraphQL GET inline", fiber.MethodGet, graphqlGet(`{ users(filter: {nameContains: "`+seededName+`"}) { edges { node { name dob } } } }`, ""), "", fiber.StatusOK},
+        {"GraphQL GET variables", fiber.MethodGet, graphqlGet(`query($filter: UserFilter) { users(filter: $filter) { edges { node { name dob } } } }`, `{"filter":{"nameContains":"`+seededName+`","bornAfter":"`+seededDOB+`"}}`), "", fiber.StatusOK},
+        {"GraphQL POST", fiber.MethodPost, "/graphql", `{"query":"mutation($input: CreateUserInput!) { createUser(input: $input) { name dob } }","variables":{"input":` + userJSON(otherName, otherDOB) + `}}`, fiber.StatusOK},
+        {"unknown route", fiber.MethodGet, "/users/1/profile", "", fiber.StatusNotFound},
+        {"delete", fiber.MethodDelete, "/users/2", "", fiber.StatusNoContent},
+    }
+    
+    for _, format := range []string{accesslog.FormatJSON, accesslog.FormatLogfmt, accesslog.FormatCombined} {
+        t.Run(format, func(t *testing.T) {
+            dir := t.TempDir()
+            app, done := newPublicApp(t, format, dir)
+            
+            for _, tt := range requests {
+                req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+                req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+                resp, err := app.Test(req, -1)
+                if err != nil {
+                    t.Fatalf("%s: request: %v", tt.name, err)
+                }
+                _, _ = io.Copy(io.Discard, resp.Body)
+                resp.Body.Close()
+                if resp.StatusCode != tt.status {
+                    t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.status)
+                }
+            }
+            done()
+            
+            accessLines := readLines(t, filepath.Join(dir, "access.log"))
+            if len(accessLines) != len(requests) {
+                t.Errorf("access log has %d lines, want %d", len(accessLines), len(requests))
+            }
+            appLines := readLines(t, filepath.Join(dir, "app.log"))
+            if !strings.Contains(strings.Join(appLines, "\n"), "Panic recovered") {
+                t.Error("application log has no recovered panic")
+            }
+            
+            for _, line := range append(accessLines, appLines...) {
+                for _, pii := range []string{seededName, seededDOB, otherName, otherDOB, url.QueryEscape(seededName)} {
+                    if strings.Contains(line, pii) {
+                        t.Errorf("log line contains %q: %s", pii, line)
+                    }
+                }
+            }
+        })
+    }
+}
+
+// newPublicApp wires the public app the way main does, logging to files in
+// dir. done flushes the logs.
+func newPublicApp(t *testing.T, format, dir string) (app *fiber.App, done func()) {
+    t.Helper()
+    
+    if err := logger.Init(logger.Config{
+        Level:       "debug",
+        Encoding:    "json",
+        OutputPaths: []string{filepath.Join(dir, "app.log")},
+    }); err != nil {
+        t.Fatalf("init logger: %v", err)
+    }
+    accessLog, err := accesslog.New(accesslog.Config{
+        Format:        format,
+        Output:        filepath.Join(dir, "access.log"),
+        Fields:        []string{accesslog.FieldUserAgent, accesslog.FieldReferer, accesslog.FieldBytesIn, accesslog.FieldBytesOut, accesslog.FieldRoute},
+        CaptureBodies: true,
+        MaxBodyBytes:  4096,
+    })
+    if err != nil {
+        t.Fatalf("access log: %v", err)
+    }
+    t.Cleanup(func() { accessLog.Close() })
+    
+    ctx, cancel := context.WithCancel(context.Background())
+    reporter := errreport.NewDispatcher([]errreport.Sink{errreport.NewLogSink(logger.Log)}, time.Minute, 16, logger.Log)
+    stopped := make(chan struct{})
+    go func() {
+        defer close(stopped)
+        _ = reporter.Run(ctx)
+    }()
+    t.Cleanup(cancel)
+    
+    sqliteDB, driver, err := database.Open("sqlite://"+filepath.Join(dir, "users.db"), 0)
+    if err != nil {
+        t.Fatalf("open sqlite: %v", err)
+    }
+    t.Cleanup(func() { sqliteDB.Close() })
+    repo := repository.NewSQLiteUserRepository(sqliteDB, events.NewBroker(zap.NewNop()))
+    if _, err := repo.Create(context.Background(), seededName, mustDate(t, seededDOB)); err != nil {
+        t.Fatalf("seed user: %v", err)
+    }
+    txManager := database.NewTxManager(sqliteDB, driver, sql.LevelDefault, 0)
+    userService := service.NewUserService(faultyRepository{repo}, txManager, logger.Log)
+    
+    resolver, err := clientip.NewResolver(nil, nil)
+    if err != nil {
+        t.Fatalf("client IP resolver: %v", err)
+    }
+    
+    app = fiber.New(fiber.Config{
+        ErrorHandler: handler.ErrorHandler,
+    })
+    app.Use(middleware.RequestID())
+    app.Use(middleware.RequestContext(logger.Log))
+    app.Use(middleware.ClientIP(resolver))
+    app.Use(middleware.Logger(accessLog, nil))
+    app.Use(middleware.Recover(logger.Log, reporter))
+    app.Use(middleware.Authenticate(auth.NewAuthenticator(nil, nil)))
+    routes.SetupRoutes(app,
+        handler.NewUserHandler(userService, logger.Log),
+        handler.NewUserEventsHandler(sse.NewHub(16), time.Second, logger.Log),
+        graph.NewHandler(userService, 10, 1000, logger.Log),
+        handler.NewHealthHandler(health.NewRegistry(time.Second), false),
+        handler.NewVersionHandler(),
+        5*time.Second,
+    )
+    
+    return app, func() {
+        cancel()
+        <-stopped
+        logger.Sync()
+    }
+}
+
+func readLines(t *testing.T, path string) []string {
+    t.Helper()
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatalf("read %s: %v", path, err)
+    }
+    return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func mustDate(t *testing.T, value string) time.Time {
+    t.Helper()
+    d, err := time.Parse("2006-01-02", value)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return d
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+    "github.com/gofiber/fiber/v2"
+    "github.com/adityaK87/go-backend-assignment/internal/version"
+)
+
+type VersionHandler struct{}
+
+func NewVersionHandler() *VersionHandler {
+    return &VersionHandler{}
+}
+
+// Get returns the version and commit the server was built from.
+func (h *VersionHandler) Get(c *fiber.Ctx) error {
+    return c.JSON(version.Get())
+}
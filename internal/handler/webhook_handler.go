@@ -0,0 +1,142 @@
+package handler
+
+import (
+    "errors"
+    "strconv"
+    
+    "github.com/go-playground/validator/v10"
+    "github.com/gofiber/fiber/v2"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/service"
+    "go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+    service   service.WebhookService
+    validator *validator.Validate
+    logger    *zap.Logger
+}
+
+func NewWebhookHandler(service service.WebhookService, logger *zap.Logger) *WebhookHandler {
+    return &WebhookHandler{
+        service:   service,
+        validator: validator.New(),
+        logger:    logger,
+    }
+}
+
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+    var req models.CreateWebhookSubscriptionRequest
+    
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Invalid request body",
+        })
+    }
+    
+    if err := h.validator.Struct(req); err != nil {
+        details := make(map[string]string)
+        for _, err := range err.(validator.ValidationErrors) {
+            details[err.Field()] = err.Tag()
+        }
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error:   "Validation failed",
+            Details: details,
+        })
+    }
+    
+    subscription, err := h.service.CreateSubscription(c.UserContext(), req)
+    if err != nil {
+        return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+            Error: err.Error(),
+        })
+    }
+    
+    return c.Status(fiber.StatusCreated).JSON(subscription)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c *fiber.Ctx) error {
+    subscriptions, err := h.service.ListSubscriptions(c.UserContext())
+    if err != nil {
+        return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+            Error: err.Error(),
+        })
+    }
+    
+    return c.JSON(subscriptions)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+    id, err := strconv.ParseInt(c.Params("id"), 10, 32)
+    if err != nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Invalid subscription ID",
+        })
+    }
+    
+    if err := h.service.DeleteSubscription(c.UserContext(), int32(id)); err != nil {
+        if errors.Is(err, service.ErrSubscriptionNotFound) {
+            return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+                Error: "Subscription not found",
+            })
+        }
+        return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+            Error: err.Error(),
+        })
+    }
+    
+    return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+    var query models.WebhookDeliveryQuery
+    
+    if err := c.QueryParser(&query); err != nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Invalid query parameters",
+        })
+    }
+    
+    if err := h.validator.Struct(query); err != nil {
+        details := make(map[string]string)
+        for _, err := range err.(validator.ValidationErrors) {
+            details[err.Field()] = err.Tag()
+        }
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error:   "Validation failed",
+            Details: details,
+        })
+    }
+    
+    deliveries, err := h.service.ListDeliveries(c.UserContext(), query)
+    if err != nil {
+        return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+            Error: err.Error(),
+        })
+    }
+    
+    return c.JSON(deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+    id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+    if err != nil {
+        return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+            Error: "Invalid delivery ID",
+        })
+    }
+    
+    delivery, err := h.service.Redeliver(c.UserContext(), id)
+    if err != nil {
+        if errors.Is(err, service.ErrDeliveryNotFound) {
+            return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+                Error: "Delivery not found",
+            })
+        }
+        return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+            Error: err.Error(),
+        })
+    }
+    
+    return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
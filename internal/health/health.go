@@ -0,0 +1,154 @@
+package health
+
+import (
+    "context"
+    "errors"
+    "sort"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+// Probe selects which endpoint a check contributes to. A check can belong to
+// several probes.
+type Probe uint8
+
+const (
+    Liveness Probe = 1 << iota
+    Readiness
+    Startup
+)
+
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check returns nil when the dependency is healthy.
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+    Name       string `json:"name"`
+    Healthy    bool   `json:"healthy"`
+    Error      string `json:"error,omitempty"`
+    DurationMS int64  `json:"duration_ms"`
+}
+
+type Report struct {
+    Healthy bool          `json:"healthy"`
+    Checks  []CheckResult `json:"checks"`
+}
+
+type registered struct {
+    name    string
+    probes  Probe
+    timeout time.Duration
+    check   Check
+    
+    // mu is held while the check runs, so a burst of probes waits for one
+    // run and then shares its cached result
+    mu      sync.Mutex
+    result  CheckResult
+    checked time.Time
+}
+
+// Registry runs named checks for the liveness, readiness and startup probes.
+// Results are cached for cacheTTL so frequent probes do not hammer
+// dependencies.
+type Registry struct {
+    cacheTTL time.Duration
+    
+    mu     sync.RWMutex
+    checks []*registered
+    
+    started      atomic.Bool
+    shuttingDown atomic.Bool
+}
+
+func NewRegistry(cacheTTL time.Duration) *Registry {
+    return &Registry{cacheTTL: cacheTTL}
+}
+
+// Register adds a check to probes. Each run is bounded by timeout.
+func (r *Registry) Register(name string, probes Probe, timeout time.Duration, check Check) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    r.checks = append(r.checks, &registered{
+        name:    name,
+        probes:  probes,
+        timeout: timeout,
+        check:   check,
+    })
+    sort.Slice(r.checks, func(i, j int) bool { return r.checks[i].name < r.checks[j].name })
+}
+
+// Shutdown makes readiness fail from now on, so load balancers stop sending
+// traffic while in-flight requests drain.
+func (r *Registry) Shutdown() {
+    r.shuttingDown.Store(true)
+}
+
+// Run evaluates the checks registered for probe. Startup succeeds for good
+// once it has succeeded once.
+func (r *Registry) Run(ctx context.Context, probe Probe) Report {
+    if probe == Startup && r.started.Load() {
+        return Report{Healthy: true, Checks: []CheckResult{}}
+    }
+    
+    r.mu.RLock()
+    var checks []*registered
+    for _, c := range r.checks {
+        if c.probes&probe != 0 {
+            checks = append(checks, c)
+        }
+    }
+    r.mu.RUnlock()
+    
+    report := Report{Healthy: true, Checks: make([]CheckResult, len(checks))}
+    var wg sync.WaitGroup
+    for i, c := range checks {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            report.Checks[i] = r.run(ctx, c)
+        }()
+    }
+    wg.Wait()
+    
+    if probe == Readiness && r.shuttingDown.Load() {
+        report.Checks = append(report.Checks, CheckResult{Name: "shutdown", Error: ErrShuttingDown.Error()})
+    }
+    for _, result := range report.Checks {
+        if !result.Healthy {
+            report.Healthy = false
+        }
+    }
+    
+    if probe == Startup && report.Healthy {
+        r.started.Store(true)
+    }
+    return report
+}
+
+func (r *Registry) run(ctx context.Context, c *registered) CheckResult {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    
+    if !c.checked.IsZero() && time.Since(c.checked) < r.cacheTTL {
+        return c.result
+    }
+    
+    ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+    defer cancel()
+    
+    start := time.Now()
+    err := c.check(ctx)
+    c.result = CheckResult{
+        Name:       c.name,
+        Healthy:    err == nil,
+        DurationMS: time.Since(start).Milliseconds(),
+    }
+    if err != nil {
+        c.result.Error = err.Error()
+    }
+    c.checked = time.Now()
+    return c.result
+}
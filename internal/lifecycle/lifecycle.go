@@ -0,0 +1,185 @@
+package lifecycle
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "os/signal"
+    "sync"
+    "sync/atomic"
+    "syscall"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+// Exit codes returned by Wait and Shutdown.
+const (
+    ExitOK      = 0
+    ExitFailure = 1
+)
+
+type Config struct {
+    // PreStopDelay keeps serving after readiness starts failing, so load
+    // balancers notice before connections are refused
+    PreStopDelay    time.Duration
+    ShutdownTimeout time.Duration
+}
+
+type component struct {
+    name string
+    stop func(ctx context.Context) error
+}
+
+// Manager starts components in registration order and stops them in
+// reverse, so everything a component depends on is still there while it
+// stops.
+type Manager struct {
+    config Config
+    logger *zap.Logger
+    
+    mu         sync.Mutex
+    components []component
+    beforeStop []func()
+    
+    failed   chan error
+    stopping atomic.Bool
+    signals  chan os.Signal
+}
+
+func New(config Config, logger *zap.Logger) *Manager {
+    m := &Manager{
+        config:  config,
+        logger:  logger,
+        failed:  make(chan error, 1),
+        signals: make(chan os.Signal, 1),
+    }
+    signal.Notify(m.signals, os.Interrupt, syscall.SIGTERM)
+    return m
+}
+
+// Register adds a component that only needs stopping, such as the database.
+func (m *Manager) Register(name string, stop func(ctx context.Context) error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// BeforeStop runs hook as soon as shutdown begins, before the pre-stop
+// delay.
+func (m *Manager) BeforeStop(hook func()) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.beforeStop = append(m.beforeStop, hook)
+}
+
+// Go runs a background worker until its context is cancelled at shutdown.
+// A worker that returns an error before then shuts the service down.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    
+    go func() {
+        defer close(done)
+        if err := run(ctx); err != nil && ctx.Err() == nil {
+            m.Fail(fmt.Errorf("%s: %w", name, err))
+        }
+    }()
+    
+    m.Register(name, func(stopCtx context.Context) error {
+        cancel()
+        select {
+        case <-done:
+            return nil
+        case <-stopCtx.Done():
+            return stopCtx.Err()
+        }
+    })
+}
+
+// Serve runs a blocking serve function, such as a listener's accept loop.
+// If it returns before shutdown the service shuts down.
+func (m *Manager) Serve(name string, serve func() error, stop func(ctx context.Context) error) {
+    var stopped atomic.Bool
+    
+    go func() {
+        err := serve()
+        if stopped.Load() {
+            return
+        }
+        if err == nil {
+            err = errors.New("stopped unexpectedly")
+        }
+        m.Fail(fmt.Errorf("%s: %w", name, err))
+    }()
+    
+    m.Register(name, func(ctx context.Context) error {
+        stopped.Store(true)
+        return stop(ctx)
+    })
+}
+
+// Fail starts a shutdown that ends with ExitFailure.
+func (m *Manager) Fail(err error) {
+    select {
+    case m.failed <- err:
+    default:
+    }
+}
+
+// Wait blocks until SIGINT, SIGTERM or a failure, then shuts down and
+// returns the exit code.
+func (m *Manager) Wait() int {
+    select {
+    case sig := <-m.signals:
+        m.logger.Info("Shutting down server...", zap.String("signal", sig.String()))
+        return m.Shutdown(nil)
+    case err := <-m.failed:
+        m.logger.Error("Component failed, shutting down", zap.Error(err))
+        return m.Shutdown(err)
+    }
+}
+
+// Shutdown stops every registered component. cause is the error that
+// triggered it, nil for a requested shutdown. A second signal skips the
+// pre-stop delay.
+func (m *Manager) Shutdown(cause error) int {
+    if !m.stopping.CompareAndSwap(false, true) {
+        return ExitFailure
+    }
+    defer signal.Stop(m.signals)
+    
+    m.mu.Lock()
+    hooks := m.beforeStop
+    components := m.components
+    m.mu.Unlock()
+    
+    for _, hook := range hooks {
+        hook()
+    }
+    
+    if cause == nil && m.config.PreStopDelay > 0 {
+        m.logger.Info("Waiting before stopping", zap.Duration("delay", m.config.PreStopDelay))
+        select {
+        case <-time.After(m.config.PreStopDelay):
+        case <-m.signals:
+        }
+    }
+    
+    ctx, cancel := context.WithTimeout(context.Background(), m.config.ShutdownTimeout)
+    defer cancel()
+    
+    code := ExitOK
+    if cause != nil {
+        code = ExitFailure
+    }
+    for i := len(components) - 1; i >= 0; i-- {
+        c := components[i]
+        if err := c.stop(ctx); err != nil {
+            m.logger.Error("Failed to stop component", zap.String("component", c.name), zap.Error(err))
+            code = ExitFailure
+        }
+    }
+    return code
+}
@@ -0,0 +1,126 @@
+package lifecycle
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "sync"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+)
+
+// recorder collects the order in which hooks and components run.
+type recorder struct {
+    mu    sync.Mutex
+    steps []string
+}
+
+func (r *recorder) add(step string) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.steps = append(r.steps, step)
+}
+
+func (r *recorder) stop(name string, err error) func(ctx context.Context) error {
+    return func(ctx context.Context) error {
+        r.add(name)
+        return err
+    }
+}
+
+func TestShutdown(t *testing.T) {
+    errStop := errors.New("stop failed")
+    
+    tests := []struct {
+        name     string
+        cause    error
+        failing  string
+        blocking bool
+        wantCode int
+        want     []string
+    }{
+        {name: "reverse registration order", wantCode: ExitOK, want: []string{"hook", "delay", "server", "worker", "database"}},
+        {name: "failure skips the pre-stop delay", cause: errors.New("listener died"), wantCode: ExitFailure, want: []string{"hook", "server", "worker", "database"}},
+        {name: "failed stop still stops the rest", failing: "worker", wantCode: ExitFailure, want: []string{"hook", "delay", "server", "worker", "database"}},
+        {name: "timeout fails the stuck component", blocking: true, wantCode: ExitFailure, want: []string{"hook", "delay", "server", "worker", "database"}},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            const delay = 20 * time.Millisecond
+            m := New(Config{PreStopDelay: delay, ShutdownTimeout: 50 * time.Millisecond}, zap.NewNop())
+            var r recorder
+            var hookAt time.Time
+            
+            m.Register("database", r.stop("database", nil))
+            m.Register("worker", func(ctx context.Context) error {
+                r.add("worker")
+                if tt.blocking {
+                    <-ctx.Done()
+                    return ctx.Err()
+                }
+                if tt.failing == "worker" {
+                    return errStop
+                }
+                return nil
+            })
+            m.Register("server", func(ctx context.Context) error {
+                if since := time.Since(hookAt); tt.cause == nil && since >= delay {
+                    r.add("delay")
+                }
+                r.add("server")
+                return nil
+            })
+            m.BeforeStop(func() {
+                hookAt = time.Now()
+                r.add("hook")
+            })
+            
+            if code := m.Shutdown(tt.cause); code != tt.wantCode {
+                t.Errorf("Shutdown() = %d, want %d", code, tt.wantCode)
+            }
+            if !slices.Equal(r.steps, tt.want) {
+                t.Errorf("steps = %v, want %v", r.steps, tt.want)
+            }
+            if code := m.Shutdown(nil); code != ExitFailure {
+                t.Errorf("second Shutdown() = %d, want %d", code, ExitFailure)
+            }
+        })
+    }
+}
+
+func TestWaitOnFailure(t *testing.T) {
+    tests := []struct {
+        name  string
+        start func(m *Manager)
+    }{
+        {"worker returns an error", func(m *Manager) {
+            m.Go("worker", func(ctx context.Context) error {
+                return errors.New("queue closed")
+            })
+        }},
+        {"server stops by itself", func(m *Manager) {
+            m.Serve("server", func() error { return nil }, func(ctx context.Context) error { return nil })
+        }},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            m := New(Config{PreStopDelay: time.Hour, ShutdownTimeout: time.Second}, zap.NewNop())
+            tt.start(m)
+            
+            done := make(chan int, 1)
+            go func() { done <- m.Wait() }()
+            select {
+            case code := <-done:
+                if code != ExitFailure {
+                    t.Errorf("Wait() = %d, want %d", code, ExitFailure)
+                }
+            case <-time.After(5 * time.Second):
+                t.Fatal("Wait did not return after a failure")
+            }
+        })
+    }
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "sync/atomic"
+    
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+)
+
+var (
+    level = zap.NewAtomicLevel()
+    // configured is the level from Init, which ToggleDebug returns to
+    configured    atomic.Int32
+    packageLevels atomic.Pointer[[]packageLevel]
+)
+
+// packageLevel overrides the global level for one package. Lists are kept
+// longest package first so the most specific match wins.
+type packageLevel struct {
+    pkg   string
+    level zapcore.Level
+}
+
+// Level returns the current global level.
+func Level() zapcore.Level {
+    return level.Level()
+}
+
+// SetLevel changes the global level of every logger derived from Log.
+func SetLevel(l zapcore.Level) {
+    level.SetLevel(l)
+}
+
+// ToggleDebug switches between debug and the configured level, falling back
+// to info when debug is the configured level, and returns the new level.
+func ToggleDebug() zapcore.Level {
+    next := zapcore.DebugLevel
+    if level.Level() == zapcore.DebugLevel {
+        next = zapcore.Level(configured.Load())
+        if next == zapcore.DebugLevel {
+            next = zapcore.InfoLevel
+        }
+    }
+    level.SetLevel(next)
+    return next
+}
+
+// PackageLevels returns the per-package overrides as package => level.
+func PackageLevels() map[string]string {
+    result := make(map[string]string)
+    for _, p := range currentPackageLevels() {
+        result[p.pkg] = p.level.String()
+    }
+    return result
+}
+
+// SetPackageLevels replaces all per-package overrides. Entries have the form
+// "package=level", where package is an import path or its trailing elements.
+func SetPackageLevels(entries []string) error {
+    overrides, err := parsePackageLevels(entries)
+    if err != nil {
+        return err
+    }
+    packageLevels.Store(&overrides)
+    return nil
+}
+
+func parsePackageLevels(entries []string) ([]packageLevel, error) {
+    overrides := make([]packageLevel, 0, len(entries))
+    for _, entry := range entries {
+        pkg, name, ok := strings.Cut(entry, "=")
+        pkg = strings.Trim(strings.TrimSpace(pkg), "/")
+        if !ok || pkg == "" {
+            return nil, fmt.Errorf("package level %q: want package=level", entry)
+        }
+        l, err := zapcore.ParseLevel(strings.TrimSpace(name))
+        if err != nil {
+            return nil, fmt.Errorf("package level %q: %w", entry, err)
+        }
+        overrides = append(overrides, packageLevel{pkg: pkg, level: l})
+    }
+    sort.SliceStable(overrides, func(i, j int) bool {
+        return len(overrides[i].pkg) > len(overrides[j].pkg)
+    })
+    return overrides, nil
+}
+
+func currentPackageLevels() []packageLevel {
+    if overrides := packageLevels.Load(); overrides != nil {
+        return *overrides
+    }
+    return nil
+}
+
+// levelCore filters entries by the global level, or by the override for the
+// package that logged them. The package is only known from the caller once
+// the entry is written, so while overrides exist filtering happens in Write.
+type levelCore struct {
+    zapcore.Core
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+    minimum := level.Level()
+    for _, p := range currentPackageLevels() {
+        if p.level < minimum {
+            minimum = p.level
+        }
+    }
+    return l >= minimum
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+    return &levelCore{Core: c.Core.With(fields)}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+    if len(currentPackageLevels()) == 0 {
+        if entry.Level < level.Level() {
+            return checked
+        }
+        return c.Core.Check(entry, checked)
+    }
+    if !c.Enabled(entry.Level) {
+        return checked
+    }
+    return checked.AddCore(entry, c)
+}
+
+func (c *levelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+    if entry.Level < levelFor(entry.Caller) {
+        return nil
+    }
+    // Going through the inner Check keeps sampling in effect
+    if inner := c.Core.Check(entry, nil); inner != nil {
+        inner.Write(fields...)
+    }
+    return nil
+}
+
+func levelFor(caller zapcore.EntryCaller) zapcore.Level {
+    overrides := currentPackageLevels()
+    if !caller.Defined || len(overrides) == 0 {
+        return level.Level()
+    }
+    pkg := packageOf(caller.Function)
+    for _, p := range overrides {
+        if pkg == p.pkg || strings.HasSuffix(pkg, "/"+p.pkg) {
+            return p.level
+        }
+    }
+    return level.Level()
+}
+
+// packageOf returns the import path from a function name such as
+// "example.com/app/internal/service.(*userService).CreateUser".
+func packageOf(function string) string {
+    slash := strings.LastIndex(function, "/")
+    dot := strings.Index(function[slash+1:], ".")
+    if dot < 0 {
+        return function
+    }
+    return function[:slash+1+dot]
+}
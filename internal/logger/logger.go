@@ -1,22 +1,86 @@
 package logger
 
 import (
+    "context"
+    
     "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
 )
 
 var Log *zap.Logger
 
-func Init() error {
-    var err error
-    // Production configuration
+// Config selects the level, encoding and destinations of the global logger.
+type Config struct {
+    Level string
+    // Encoding is "json" or "console"
+    Encoding string
+    // OutputPaths are "stdout", "stderr" or file paths
+    OutputPaths []string
+    // Sampling keeps the first SamplingInitial entries with the same level
+    // and message each second, then every SamplingThereafter-th; a zero
+    // SamplingThereafter disables it
+    SamplingInitial    int
+    SamplingThereafter int
+    // StacktraceLevel adds stack traces to entries at or above it; empty
+    // disables them
+    StacktraceLevel string
+    // PackageLevels override Level for entries logged from a package, as
+    // "internal/service=debug"
+    PackageLevels []string
+}
+
+// Init builds the global logger. Its level can be changed afterwards with
+// SetLevel, SetPackageLevels and ToggleDebug.
+func Init(cfg Config) error {
+    base, err := zapcore.ParseLevel(cfg.Level)
+    if err != nil {
+        return err
+    }
+    overrides, err := parsePackageLevels(cfg.PackageLevels)
+    if err != nil {
+        return err
+    }
+    
+    // Production configuration; levelCore does the level filtering so the
+    // inner core accepts everything
     config := zap.NewProductionConfig()
+    config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
     config.DisableStacktrace = true
+    config.Encoding = redactedPrefix + cfg.Encoding
+    if cfg.Encoding == "console" {
+        config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+    }
+    if len(cfg.OutputPaths) > 0 {
+        config.OutputPaths = cfg.OutputPaths
+    }
+    config.Sampling = nil
+    if cfg.SamplingThereafter > 0 {
+        config.Sampling = &zap.SamplingConfig{
+            Initial:    cfg.SamplingInitial,
+            Thereafter: cfg.SamplingThereafter,
+        }
+    }
+    
+    options := []zap.Option{zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+        return &levelCore{Core: core}
+    })}
+    if cfg.StacktraceLevel != "" {
+        stacktrace, err := zapcore.ParseLevel(cfg.StacktraceLevel)
+        if err != nil {
+            return err
+        }
+        options = append(options, zap.AddStacktrace(stacktrace))
+    }
     
-    Log, err = config.Build()
+    log, err := config.Build(options...)
     if err != nil {
         return err
     }
     
+    configured.Store(int32(base))
+    level.SetLevel(base)
+    packageLevels.Store(&overrides)
+    Log = log
     zap.ReplaceGlobals(Log)
     return nil
 }
@@ -25,4 +89,28 @@ func Sync() {
     if Log != nil {
         _ = Log.Sync()
     }
-}
\ No newline at end of file
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, normally a logger with
+// request fields attached.
+func NewContext(ctx context.Context, l *zap.Logger) context.Context {
+    return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger in ctx, or the global logger if there is
+// none.
+func FromContext(ctx context.Context) *zap.Logger {
+    return FromContextOr(ctx, Log)
+}
+
+// FromContextOr returns the logger in ctx, or fallback if there is none.
+// Components built with their own logger use it so that request fields are
+// added when they are called on behalf of a request.
+func FromContextOr(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+    if l, ok := ctx.Value(contextKey{}).(*zap.Logger); ok {
+        return l
+    }
+    return fallback
+}
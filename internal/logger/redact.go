@@ -0,0 +1,123 @@
+package logger
+
+import (
+    "time"
+    
+    "go.uber.org/zap"
+    "go.uber.org/zap/buffer"
+    "go.uber.org/zap/zapcore"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+)
+
+// Init selects these in place of the plain "json" and "console" encodings.
+const redactedPrefix = "redacted-"
+
+func init() {
+    encoders := map[string]func(zapcore.EncoderConfig) zapcore.Encoder{
+        "json":    zapcore.NewJSONEncoder,
+        "console": zapcore.NewConsoleEncoder,
+    }
+    for name, build := range encoders {
+        err := zap.RegisterEncoder(redactedPrefix+name, func(config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+            return NewRedactingEncoder(build(config)), nil
+        })
+        if err != nil {
+            panic(err)
+        }
+    }
+}
+
+// redactingEncoder applies the redact policy to every field: values under
+// PII keys are masked, PII fields inside structs logged with zap.Any are
+// masked, and error messages go through redact.Message, since database
+// errors tend to echo the parameters they failed on.
+type redactingEncoder struct {
+    zapcore.Encoder
+}
+
+// NewRedactingEncoder wraps encoder with the redact policy. Init uses it for
+// the global logger; it is exported for cores built elsewhere, such as in
+// tests.
+func NewRedactingEncoder(encoder zapcore.Encoder) zapcore.Encoder {
+    return redactingEncoder{Encoder: encoder}
+}
+
+func (e redactingEncoder) Clone() zapcore.Encoder {
+    return redactingEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e redactingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+    return e.Encoder.EncodeEntry(entry, redactFields(fields))
+}
+
+// The Add methods are used for fields given to With, which bypass
+// EncodeEntry.
+
+func (e redactingEncoder) AddString(key, value string) {
+    if redact.Key(key) {
+        value = redact.Mask
+    }
+    e.Encoder.AddString(key, value)
+}
+
+func (e redactingEncoder) AddByteString(key string, value []byte) {
+    if redact.Key(key) {
+        e.Encoder.AddString(key, redact.Mask)
+        return
+    }
+    e.Encoder.AddByteString(key, value)
+}
+
+func (e redactingEncoder) AddTime(key string, value time.Time) {
+    if redact.Key(key) {
+        e.Encoder.AddString(key, redact.Mask)
+        return
+    }
+    e.Encoder.AddTime(key, value)
+}
+
+func (e redactingEncoder) AddReflected(key string, value interface{}) error {
+    if redact.Key(key) {
+        e.Encoder.AddString(key, redact.Mask)
+        return nil
+    }
+    return e.Encoder.AddReflected(key, redact.Value(value))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+    var result []zapcore.Field
+    for i, field := range fields {
+        replaced, ok := redactField(field)
+        if !ok {
+            if result != nil {
+                result = append(result, field)
+            }
+            continue
+        }
+        if result == nil {
+            result = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+        }
+        result = append(result, replaced)
+    }
+    if result == nil {
+        return fields
+    }
+    return result
+}
+
+// redactField reports false when field needs no change.
+func redactField(field zapcore.Field) (zapcore.Field, bool) {
+    switch {
+    case field.Type == zapcore.SkipType || field.Type == zapcore.NamespaceType:
+        return field, false
+    case redact.Key(field.Key):
+        return zap.String(field.Key, redact.Mask), true
+    case field.Type == zapcore.ErrorType:
+        err, _ := field.Interface.(error)
+        return zap.String(field.Key, redact.Error(err)), true
+    case field.Type == zapcore.ReflectType:
+        return zap.Reflect(field.Key, redact.Value(field.Interface)), true
+    }
+    return field, false
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package logger
+
+import (
+    "context"
+)
+
+// WatchLevelSignal waits for ctx; there is no SIGUSR1 on this platform, so
+// the level can only be changed through SetLevel.
+func WatchLevelSignal(ctx context.Context) error {
+    <-ctx.Done()
+    return nil
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package logger
+
+import (
+    "context"
+    "os"
+    "os/signal"
+    "syscall"
+    
+    "go.uber.org/zap"
+)
+
+// WatchLevelSignal toggles debug logging each time the process receives
+// SIGUSR1, until ctx is done.
+func WatchLevelSignal(ctx context.Context) error {
+    signals := make(chan os.Signal, 1)
+    signal.Notify(signals, syscall.SIGUSR1)
+    defer signal.Stop(signals)
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-signals:
+            l := ToggleDebug()
+            Log.Warn("Log level changed by signal", zap.Stringer("level", l))
+        }
+    }
+}
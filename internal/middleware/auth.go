@@ -0,0 +1,69 @@
+package middleware
+
+import (
+    "strings"
+    
+    "github.com/gofiber/fiber/v2"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/auth"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+)
+
+// Authenticate resolves an API key from "Authorization: Bearer <key>" or
+// X-API-Key, or else a verified TLS client certificate, and puts the
+// principal in the request context. Requests without either continue
+// anonymously; a wrong key is rejected. It must run after RequestContext.
+func Authenticate(authenticator *auth.Authenticator) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        key := c.Get("X-API-Key")
+        if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+            key = bearer
+        }
+        
+        var principal *auth.Principal
+        if key != "" {
+            var err error
+            principal, err = authenticator.AuthenticateAPIKey(key)
+            if err != nil {
+                return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+                    Error: "Invalid API key",
+                })
+            }
+        } else if state := c.Context().TLSConnectionState(); state != nil && len(state.VerifiedChains) > 0 {
+            // Chains are only recorded for certificates that verified
+            // against the client CA bundle
+            principal = authenticator.AuthenticateCertificate(state.VerifiedChains[0][0])
+        }
+        if principal == nil {
+            return c.Next()
+        }
+        
+        ctx := auth.NewContext(c.UserContext(), principal)
+        ctx = logger.NewContext(ctx, logger.FromContext(ctx).With(zap.String("principal", principal.Subject)))
+        c.SetUserContext(ctx)
+        return c.Next()
+    }
+}
+
+// RequireScope lets through only principals holding scope, answering 401
+// to anonymous requests and 403 to the rest. It must run after
+// Authenticate.
+func RequireScope(scope string) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        principal := auth.FromContext(c.UserContext())
+        if principal == nil {
+            c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+            return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+                Error: "Authentication required",
+            })
+        }
+        if !principal.HasScope(scope) {
+            return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+                Error: "Missing scope " + scope,
+            })
+        }
+        return c.Next()
+    }
+}
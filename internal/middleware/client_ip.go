@@ -0,0 +1,33 @@
+package middleware
+
+import (
+    "github.com/gofiber/fiber/v2"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/clientip"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+)
+
+// ClientIP resolves the client address and scheme behind trusted proxies
+// and puts them in the request context for clientip.FromContext. Forwarding
+// headers from any other peer are ignored. It must run after
+// RequestContext.
+func ClientIP(resolver *clientip.Resolver) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        scheme := "http"
+        if c.Context().IsTLS() {
+            scheme = "https"
+        }
+        peer := c.Context().RemoteIP().String()
+        
+        client, err := resolver.Resolve(peer, scheme, func(name string) string {
+            return c.Get(name)
+        })
+        ctx := c.UserContext()
+        if err != nil {
+            logger.FromContext(ctx).Debug("Ignoring forwarding headers", zap.String("peer", peer), zap.Error(err))
+        }
+        c.SetUserContext(clientip.NewContext(ctx, client))
+        return c.Next()
+    }
+}
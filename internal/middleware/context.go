@@ -0,0 +1,105 @@
+package middleware
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+    
+    "github.com/gofiber/fiber/v2"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+    "github.com/adityaK87/go-backend-assignment/internal/traceid"
+)
+
+// StatusClientClosedRequest is logged for requests whose client went away,
+// following the nginx convention.
+const StatusClientClosedRequest = 499
+
+// disconnectPollInterval is how often a running request checks whether its
+// client is still connected.
+const disconnectPollInterval = 250 * time.Millisecond
+
+var ErrClientClosed = errors.New("client closed the connection")
+
+// RequestContext gives every request a real context.Context, available
+// through c.UserContext(), carrying the request ID, the trace ID from an
+// incoming traceparent header (or a new one) and a logger tagged with both.
+// The context is cancelled when the handler returns or the client
+// disconnects. It must run after RequestID.
+func RequestContext(log *zap.Logger) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        requestID, _ := c.Locals("requestID").(string)
+        
+        ctx, cancel := context.WithCancelCause(context.Background())
+        defer cancel(context.Canceled)
+        
+        traceID := traceid.FromHeader(c.Get(traceid.Header))
+        ctx = requestid.NewContext(ctx, requestID)
+        ctx = traceid.NewContext(ctx, traceID)
+        ctx = logger.NewContext(ctx, log.With(
+            zap.String("request_id", requestID),
+            zap.String("trace_id", traceID),
+        ))
+        c.SetUserContext(ctx)
+        
+        stop := watchDisconnect(c, func() { cancel(ErrClientClosed) })
+        err := c.Next()
+        stop()
+        
+        if errors.Is(context.Cause(ctx), ErrClientClosed) {
+            c.Status(StatusClientClosedRequest)
+        }
+        return err
+    }
+}
+
+// Route tags the request logger with the matched route pattern, such as
+// "/users/:id". Middleware added with app.Use runs before routing, so this
+// has to be registered on the routes themselves.
+func Route() fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        ctx := c.UserContext()
+        c.SetUserContext(logger.NewContext(ctx, logger.FromContext(ctx).With(zap.String("route", c.Route().Path))))
+        return c.Next()
+    }
+}
+
+// watchDisconnect calls onClose if the client closes the connection before
+// the returned stop function is called. Platforms that cannot peek at the
+// socket never detect a disconnect.
+func watchDisconnect(c *fiber.Ctx, onClose func()) (stop func()) {
+    conn := c.Context().Conn()
+    if conn == nil || !canDetectClose {
+        return func() {}
+    }
+    
+    var mu sync.Mutex
+    stopped := false
+    var timer *time.Timer
+    check := func() {
+        mu.Lock()
+        defer mu.Unlock()
+        if stopped {
+            return
+        }
+        if peerClosed(conn) {
+            onClose()
+            return
+        }
+        timer.Reset(disconnectPollInterval)
+    }
+    
+    mu.Lock()
+    timer = time.AfterFunc(disconnectPollInterval, check)
+    mu.Unlock()
+    
+    return func() {
+        mu.Lock()
+        defer mu.Unlock()
+        stopped = true
+        timer.Stop()
+    }
+}
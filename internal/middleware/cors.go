@@ -0,0 +1,53 @@
+package middleware
+
+import (
+    "sort"
+    "strings"
+    
+    "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORSPolicy is the CORS policy for the paths under Prefix.
+type CORSPolicy struct {
+    Prefix string
+    Config cors.Config
+}
+
+// CORS answers preflights and sets CORS headers using the policy with the
+// longest prefix matching the request path, or def if none does. It runs
+// ahead of routing so preflights reach it for every path.
+func CORS(def cors.Config, policies []CORSPolicy) fiber.Handler {
+    type prefixed struct {
+        prefix  string
+        handler fiber.Handler
+    }
+    handlers := make([]prefixed, 0, len(policies))
+    for _, p := range policies {
+        handlers = append(handlers, prefixed{prefix: p.Prefix, handler: cors.New(p.Config)})
+    }
+    sort.SliceStable(handlers, func(i, j int) bool {
+        return len(handlers[i].prefix) > len(handlers[j].prefix)
+    })
+    fallback := cors.New(def)
+    
+    return func(c *fiber.Ctx) error {
+        for _, h := range handlers {
+            if hasPathPrefix(c.Path(), h.prefix) {
+                return h.handler(c)
+            }
+        }
+        return fallback(c)
+    }
+}
+
+// hasPathPrefix reports whether path is prefix or below it, so /admin
+// matches /admin/log-level but not /administrator.
+func hasPathPrefix(path, prefix string) bool {
+    prefix = strings.TrimSuffix(prefix, "/")
+    if prefix == "" {
+        return true
+    }
+    rest, ok := strings.CutPrefix(path, prefix)
+    return ok && (rest == "" || rest[0] == '/')
+}
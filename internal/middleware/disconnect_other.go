@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package middleware
+
+import (
+    "net"
+)
+
+const canDetectClose = false
+
+func peerClosed(net.Conn) bool {
+    return false
+}
@@ -0,0 +1,45 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package middleware
+
+import (
+    "errors"
+    "net"
+    "syscall"
+)
+
+const canDetectClose = true
+
+// peerClosed peeks at the socket without consuming anything: a zero-byte
+// read means the client sent FIN, and any error other than "no data yet"
+// means the connection is gone. Pipelined request bytes read as open.
+func peerClosed(conn net.Conn) bool {
+    // TLS connections wrap the TCP connection
+    if wrapped, ok := conn.(interface{ NetConn() net.Conn }); ok {
+        conn = wrapped.NetConn()
+    }
+    sc, ok := conn.(syscall.Conn)
+    if !ok {
+        return false
+    }
+    raw, err := sc.SyscallConn()
+    if err != nil {
+        return false
+    }
+    
+    closed := false
+    var buf [1]byte
+    err = raw.Read(func(fd uintptr) bool {
+        n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+        switch {
+        case err == nil:
+            closed = n == 0
+        case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EINTR):
+        default:
+            closed = true
+        }
+        // Never block waiting for readability
+        return true
+    })
+    return closed || err != nil
+}
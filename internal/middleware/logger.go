@@ -1,35 +1,88 @@
 package middleware
 
 import (
+    "slices"
     "time"
     
     "github.com/gofiber/fiber/v2"
     "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/accesslog"
+    "github.com/adityaK87/go-backend-assignment/internal/auth"
+    "github.com/adityaK87/go-backend-assignment/internal/clientip"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+    "github.com/adityaK87/go-backend-assignment/internal/traceid"
 )
 
-func Logger(logger *zap.Logger) fiber.Handler {
+// Logger writes an access log line per request, except for skipPaths.
+// Responses with a 5xx status are logged at error level, and also to the
+// request logger so they show up next to the application's own lines. It
+// must run after RequestContext.
+func Logger(access *accesslog.Logger, skipPaths []string) fiber.Handler {
     return func(c *fiber.Ctx) error {
+        if slices.Contains(skipPaths, c.Path()) {
+            return c.Next()
+        }
+        
         start := time.Now()
         
-        // Process request
-        err := c.Next()
+        // Process request. Errors are handled here rather than by the app's
+        // error handler so that the logged status is the one sent
+        chainErr := c.Next()
+        if chainErr != nil {
+            if err := c.App().ErrorHandler(c, chainErr); err != nil {
+                _ = c.SendStatus(fiber.StatusInternalServerError)
+            }
+        }
         
         // Calculate duration
         duration := time.Since(start)
         
-        // Get request ID
-        requestID, _ := c.Locals("requestID").(string)
+        ctx := c.UserContext()
+        entry := accesslog.Entry{
+            Time:      start,
+            Level:     "info",
+            RemoteIP:  clientip.FromContext(c.UserContext()).IP,
+            Method:    c.Method(),
+            URI:       redact.URI(c.OriginalURL()),
+            Protocol:  string(c.Request().Header.Protocol()),
+            Route:     c.Route().Path,
+            Status:    c.Response().StatusCode(),
+            Duration:  duration,
+            BytesIn:   len(c.Request().Body()),
+            BytesOut:  -1,
+            Referer:   c.Get(fiber.HeaderReferer),
+            UserAgent: c.Get(fiber.HeaderUserAgent),
+            RequestID: requestid.FromContext(ctx),
+            TraceID:   traceid.FromContext(ctx),
+        }
+        entry.RequestContentType = c.Get(fiber.HeaderContentType)
+        entry.RequestBody = c.Request().Body()
+        // Reading the body of a streamed response would consume the stream
+        if !c.Response().IsBodyStream() {
+            entry.ResponseContentType = string(c.Response().Header.ContentType())
+            entry.ResponseBody = c.Response().Body()
+            entry.BytesOut = len(entry.ResponseBody)
+        }
+        if principal := auth.FromContext(ctx); principal != nil {
+            entry.Principal = principal.Subject
+        }
         
-        // Log request
-        logger.Info("HTTP Request",
-            zap.String("request_id", requestID),
-            zap.String("method", c.Method()),
-            zap.String("path", c.Path()),
-            zap.Int("status", c.Response().StatusCode()),
-            zap.Duration("duration", duration),
-            zap.String("ip", c.IP()),
-        )
+        if entry.Status >= fiber.StatusInternalServerError {
+            entry.Level = "error"
+            logger.FromContext(ctx).Error("HTTP request failed",
+                zap.String("method", entry.Method),
+                zap.String("path", c.Path()),
+                zap.Int("status", entry.Status),
+                zap.Duration("duration", duration),
+                zap.Error(chainErr),
+            )
+        }
         
-        return err
+        // Log request
+        access.Log(entry)
+        return nil
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+    "fmt"
+    "strconv"
+    "time"
+    
+    "github.com/gofiber/fiber/v2"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+)
+
+// MaxProfileDuration rejects requests asking for more than limit through
+// ?seconds=, which CPU profiles and execution traces run for.
+func MaxProfileDuration(limit time.Duration) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        raw := c.Query("seconds")
+        if raw == "" {
+            return c.Next()
+        }
+        
+        seconds, err := strconv.ParseFloat(raw, 64)
+        if err != nil || seconds <= 0 || time.Duration(seconds*float64(time.Second)) > limit {
+            return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+                Error: fmt.Sprintf("seconds must be a positive number up to %g", limit.Seconds()),
+            })
+        }
+        return c.Next()
+    }
+}
@@ -1,25 +1,83 @@
 package middleware
 
 import (
+    "errors"
+    "runtime/debug"
+    
     "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/utils"
     "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/errreport"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
+    "github.com/adityaK87/go-backend-assignment/internal/requestid"
+    "github.com/adityaK87/go-backend-assignment/internal/traceid"
 )
 
-func Recover(logger *zap.Logger) fiber.Handler {
-    return func(c *fiber.Ctx) error {
+// Recover turns a panic into a 500 response carrying the request ID, logs
+// it with its stack and sends it to reporter together with any other 5xx
+// response. It must run after Logger, so the access log sees the 500, and
+// after RequestContext.
+func Recover(log *zap.Logger, reporter *errreport.Dispatcher) fiber.Handler {
+    return func(c *fiber.Ctx) (err error) {
         defer func() {
             if r := recover(); r != nil {
-                logger.Error("Panic recovered",
-                    zap.Any("error", r),
-                    zap.String("path", c.Path()),
-                )
-                
-                c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-                    "error": "Internal server error",
-                })
+                err = recovered(c, log, reporter, r, errreport.TrimStack(debug.Stack()))
             }
         }()
         
-        return c.Next()
+        err = c.Next()
+        
+        // A returned error has not been through the error handler yet, so
+        // its status is worked out the same way
+        status := c.Response().StatusCode()
+        if err != nil {
+            status = fiber.StatusInternalServerError
+            var fiberErr *fiber.Error
+            if errors.As(err, &fiberErr) {
+                status = fiberErr.Code
+            }
+        }
+        if status >= fiber.StatusInternalServerError {
+            message := utils.StatusMessage(status)
+            if err != nil {
+                message = redact.Error(err)
+            }
+            reporter.Report(report(c, errreport.KindError, status, message, ""))
+        }
+        return err
+    }
+}
+
+func recovered(c *fiber.Ctx, log *zap.Logger, reporter *errreport.Dispatcher, r any, stack string) error {
+    ctx := c.UserContext()
+    message := redact.Recovered(r)
+    logger.FromContextOr(ctx, log).Error("Panic recovered",
+        zap.String("error", message),
+        zap.String("path", c.Path()),
+        zap.String("stack", stack),
+    )
+    reporter.Report(report(c, errreport.KindPanic, fiber.StatusInternalServerError, message, stack))
+    
+    return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+        Error:     "Internal server error",
+        RequestID: requestid.FromContext(ctx),
+    })
+}
+
+func report(c *fiber.Ctx, kind errreport.Kind, status int, message, stack string) errreport.Report {
+    ctx := c.UserContext()
+    return errreport.Report{
+        Kind:      kind,
+        Message:   message,
+        Stack:     stack,
+        RequestID: requestid.FromContext(ctx),
+        TraceID:   traceid.FromContext(ctx),
+        Method:    c.Method(),
+        Path:      c.Path(),
+        Route:     c.Route().Path,
+        Status:    status,
     }
-}
\ No newline at end of file
+}
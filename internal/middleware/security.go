@@ -0,0 +1,31 @@
+package middleware
+
+import (
+    "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/middleware/helmet"
+)
+
+// SecurityHeaders sets hardening headers on every response. The API only
+// serves JSON, so cfg should carry a locked-down Content-Security-Policy;
+// paths under docsPath serve a page and get docsCSP instead.
+func SecurityHeaders(cfg helmet.Config, docsPath, docsCSP string) fiber.Handler {
+    api := helmet.New(cfg)
+    cfg.ContentSecurityPolicy = docsCSP
+    docs := helmet.New(cfg)
+    
+    return func(c *fiber.Ctx) error {
+        if docsPath != "" && hasPathPrefix(c.Path(), docsPath) {
+            return docs(c)
+        }
+        return api(c)
+    }
+}
+
+// NoStore keeps responses out of browser and proxy caches, for routes that
+// return personal data.
+func NoStore() fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        c.Set(fiber.HeaderCacheControl, "no-store")
+        return c.Next()
+    }
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+    "context"
+    "errors"
+    "time"
+    
+    "github.com/gofiber/fiber/v2"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+)
+
+// Timeout puts a deadline on the request context for the routes it is
+// attached to, answering 504 if it expires. Zero means no deadline, for
+// long-lived routes such as event streams.
+func Timeout(timeout time.Duration) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        if timeout <= 0 {
+            return c.Next()
+        }
+        
+        ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+        defer cancel()
+        c.SetUserContext(ctx)
+        
+        err := c.Next()
+        if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+            return c.Status(fiber.StatusGatewayTimeout).JSON(models.ErrorResponse{
+                Error: "Request timed out",
+            })
+        }
+        return err
+    }
+}
@@ -0,0 +1,13 @@
+package models
+
+// LogLevelRequest changes the global level, the per-package overrides or
+// both. A non-nil Packages replaces every existing override.
+type LogLevelRequest struct {
+    Level    string            `json:"level" validate:"omitempty,oneof=debug info warn error"`
+    Packages map[string]string `json:"packages" validate:"omitempty,dive,keys,required,endkeys,oneof=debug info warn error"`
+}
+
+type LogLevelResponse struct {
+    Level    string            `json:"level"`
+    Packages map[string]string `json:"packages"`
+}
@@ -2,28 +2,47 @@ package models
 
 import (
     "time"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
 )
 
+// Fields tagged pii hold personal data and are masked in logs and error
+// output.
+func init() {
+    redact.Register(CreateUserRequest{})
+    redact.Register(UpdateUserRequest{})
+    redact.Register(UserResponse{})
+    redact.Register(UserFilter{})
+}
+
 type CreateUserRequest struct {
-    Name string `json:"name" validate:"required,min=2,max=100"`
-    DOB  string `json:"dob" validate:"required,datetime=2006-01-02"`
+    Name string `json:"name" validate:"required,min=2,max=100" pii:"true"`
+    DOB  string `json:"dob" validate:"required,datetime=2006-01-02" pii:"true"`
 }
 
 type UpdateUserRequest struct {
-    Name string `json:"name" validate:"required,min=2,max=100"`
-    DOB  string `json:"dob" validate:"required,datetime=2006-01-02"`
+    Name string `json:"name" validate:"required,min=2,max=100" pii:"true"`
+    DOB  string `json:"dob" validate:"required,datetime=2006-01-02" pii:"true"`
 }
 
 type UserResponse struct {
     ID   int32  `json:"id"`
-    Name string `json:"name"`
-    DOB  string `json:"dob"`
+    Name string `json:"name" pii:"true"`
+    DOB  string `json:"dob" pii:"true"`
     Age  *int   `json:"age,omitempty"`
 }
 
 type ErrorResponse struct {
     Error   string                 `json:"error"`
     Details map[string]string      `json:"details,omitempty"`
+    // RequestID is set on 500 responses, for matching a report to the logs
+    RequestID string `json:"request_id,omitempty"`
+}
+
+type UserFilter struct {
+    NameContains string `json:"name_contains" pii:"true"`
+    BornAfter    string `json:"born_after" validate:"omitempty,datetime=2006-01-02"`
+    BornBefore   string `json:"born_before" validate:"omitempty,datetime=2006-01-02"`
 }
 
 type PaginationQuery struct {
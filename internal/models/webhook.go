@@ -0,0 +1,43 @@
+package models
+
+import (
+    "encoding/json"
+    "time"
+)
+
+type CreateWebhookSubscriptionRequest struct {
+    URL        string   `json:"url" validate:"required,url"`
+    EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=user.created user.updated user.deleted"`
+    Secret     string   `json:"secret" validate:"omitempty,min=16"`
+}
+
+type WebhookSubscriptionResponse struct {
+    ID         int32     `json:"id"`
+    URL        string    `json:"url"`
+    EventTypes []string  `json:"event_types"`
+    Secret     string    `json:"secret,omitempty"`
+    Active     bool      `json:"active"`
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryQuery struct {
+    Status         string `query:"status" validate:"omitempty,oneof=pending succeeded dead"`
+    SubscriptionID int32  `query:"subscription_id"`
+    Page           int    `query:"page" validate:"omitempty,min=1"`
+    Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type WebhookDeliveryResponse struct {
+    ID             int64           `json:"id"`
+    SubscriptionID int32           `json:"subscription_id"`
+    EventID        *int64          `json:"event_id,omitempty"`
+    EventType      string          `json:"event_type"`
+    Payload        json.RawMessage `json:"payload"`
+    Status         string          `json:"status"`
+    Attempts       int32           `json:"attempts"`
+    NextAttemptAt  time.Time       `json:"next_attempt_at"`
+    LastStatusCode *int32          `json:"last_status_code,omitempty"`
+    LastError      *string         `json:"last_error,omitempty"`
+    CreatedAt      time.Time       `json:"created_at"`
+    DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,29 @@
+package outbox
+
+import (
+    "fmt"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+const publishTimeout = 10 * time.Second
+
+// NewPublisher builds the external publisher selected by kind: "log",
+// "file", "http" or "nats". target is the file path, URL or NATS address.
+func NewPublisher(kind, target, subjectPrefix string, logger *zap.Logger) (events.Publisher, error) {
+    switch kind {
+    case "", "log":
+        return NewLogPublisher(logger), nil
+    case "file":
+        return NewFilePublisher(target)
+    case "http":
+        return NewHTTPPublisher(target, publishTimeout), nil
+    case "nats":
+        return NewNATSPublisher(target, subjectPrefix, publishTimeout), nil
+    default:
+        return nil, fmt.Errorf("unknown outbox publisher %q", kind)
+    }
+}
@@ -0,0 +1,131 @@
+package outbox
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net"
+    "strings"
+    "sync"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+// NATSPublisher speaks the minimal subset of the NATS client protocol
+// needed to publish (CONNECT, PUB, PING/PONG), so events can go to a NATS
+// server or any compatible stand-in without pulling in a client library.
+// Events are published to "<prefix>.<event type>".
+type NATSPublisher struct {
+    addr    string
+    prefix  string
+    timeout time.Duration
+    
+    mu   sync.Mutex
+    conn net.Conn
+    w    *bufio.Writer
+}
+
+func NewNATSPublisher(addr, prefix string, timeout time.Duration) *NATSPublisher {
+    return &NATSPublisher{
+        addr:    strings.TrimPrefix(addr, "nats://"),
+        prefix:  prefix,
+        timeout: timeout,
+    }
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event events.Event) error {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    subject := p.prefix + "." + string(event.Type)
+    
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    
+    if p.conn == nil {
+        if err := p.connect(ctx); err != nil {
+            return err
+        }
+    }
+    
+    _ = p.conn.SetWriteDeadline(time.Now().Add(p.timeout))
+    fmt.Fprintf(p.w, "PUB %s %d\r\n", subject, len(payload))
+    p.w.Write(payload)
+    p.w.WriteString("\r\n")
+    if err := p.w.Flush(); err != nil {
+        p.closeLocked()
+        return err
+    }
+    return nil
+}
+
+func (p *NATSPublisher) connect(ctx context.Context) error {
+    dialer := net.Dialer{Timeout: p.timeout}
+    conn, err := dialer.DialContext(ctx, "tcp", p.addr)
+    if err != nil {
+        return err
+    }
+    
+    // The server greets with INFO before accepting CONNECT
+    r := bufio.NewReader(conn)
+    _ = conn.SetReadDeadline(time.Now().Add(p.timeout))
+    line, err := r.ReadString('\n')
+    if err != nil || !strings.HasPrefix(line, "INFO") {
+        conn.Close()
+        return fmt.Errorf("nats: unexpected greeting %q: %v", strings.TrimSpace(line), err)
+    }
+    _ = conn.SetReadDeadline(time.Time{})
+    
+    w := bufio.NewWriter(conn)
+    w.WriteString(`CONNECT {"verbose":false,"pedantic":false,"name":"user-service-outbox"}` + "\r\n")
+    if err := w.Flush(); err != nil {
+        conn.Close()
+        return err
+    }
+    
+    p.conn = conn
+    p.w = w
+    go p.readLoop(conn, r)
+    return nil
+}
+
+// readLoop answers server PINGs so the connection is not dropped as stale.
+func (p *NATSPublisher) readLoop(conn net.Conn, r *bufio.Reader) {
+    for {
+        line, err := r.ReadString('\n')
+        if err != nil {
+            p.mu.Lock()
+            if p.conn == conn {
+                p.closeLocked()
+            }
+            p.mu.Unlock()
+            return
+        }
+        if strings.HasPrefix(line, "PING") {
+            p.mu.Lock()
+            if p.conn == conn {
+                p.w.WriteString("PONG\r\n")
+                _ = p.w.Flush()
+            }
+            p.mu.Unlock()
+        }
+    }
+}
+
+func (p *NATSPublisher) closeLocked() {
+    if p.conn != nil {
+        p.conn.Close()
+        p.conn = nil
+        p.w = nil
+    }
+}
+
+func (p *NATSPublisher) Close() error {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.closeLocked()
+    return nil
+}
@@ -0,0 +1,119 @@
+package outbox
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "sync"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+// MultiPublisher publishes to every publisher in order and stops at the
+// first error; the relay then retries the event for all of them.
+type MultiPublisher []events.Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event events.Event) error {
+    for _, publisher := range m {
+        if err := publisher.Publish(ctx, event); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+type LogPublisher struct {
+    logger *zap.Logger
+}
+
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+    return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event events.Event) error {
+    p.logger.Info("User event",
+        zap.Int64("event_id", event.ID),
+        zap.String("type", string(event.Type)),
+        zap.Int32("user_id", event.UserID),
+    )
+    return nil
+}
+
+// FilePublisher appends events to a file as JSON lines.
+type FilePublisher struct {
+    mu   sync.Mutex
+    file *os.File
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+    if err != nil {
+        return nil, err
+    }
+    return &FilePublisher{file: file}, nil
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, event events.Event) error {
+    line, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if _, err := p.file.Write(append(line, '\n')); err != nil {
+        return err
+    }
+    return p.file.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+    return p.file.Close()
+}
+
+// HTTPPublisher POSTs each event as JSON and treats any non-2xx response as
+// a failure.
+type HTTPPublisher struct {
+    url    string
+    client *http.Client
+}
+
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+    return &HTTPPublisher{
+        url:    url,
+        client: &http.Client{Timeout: timeout},
+    }
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event events.Event) error {
+    body, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Idempotency-Key", fmt.Sprintf("outbox-%d", event.ID))
+    
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    _, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+    
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("unexpected status %d", resp.StatusCode)
+    }
+    return nil
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "math/rand"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+type Config struct {
+    PollInterval time.Duration
+    BatchSize    int32
+    Retention    time.Duration
+    // Lease is how long a claimed batch is hidden from other relays; a
+    // batch that is still publishing when it runs out is left for a retry
+    Lease       time.Duration
+    MaxAttempts int
+    BaseBackoff time.Duration
+    MaxBackoff  time.Duration
+}
+
+func DefaultConfig() Config {
+    return Config{
+        PollInterval: 500 * time.Millisecond,
+        BatchSize:    100,
+        Retention:    7 * 24 * time.Hour,
+        Lease:        30 * time.Second,
+        MaxAttempts:  10,
+        BaseBackoff:  time.Second,
+        MaxBackoff:   5 * time.Minute,
+    }
+}
+
+// Relay moves committed outbox rows to a Publisher. Rows are leased in a
+// short transaction and only marked published after Publish succeeds, which
+// gives at-least-once delivery; several relays may run concurrently. Failed
+// rows are retried with exponential backoff and marked dead after
+// MaxAttempts, which unblocks later events for the same user.
+type Relay struct {
+    db        *sql.DB
+    queries   *db.Queries
+    publisher events.Publisher
+    config    Config
+    logger    *zap.Logger
+}
+
+func NewRelay(database *sql.DB, publisher events.Publisher, config Config, logger *zap.Logger) *Relay {
+    return &Relay{
+        db:        database,
+        queries:   db.New(database),
+        publisher: publisher,
+        config:    config,
+        logger:    logger,
+    }
+}
+
+// Run blocks until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+    ticker := time.NewTicker(r.config.PollInterval)
+    defer ticker.Stop()
+    
+    prune := time.NewTicker(time.Hour)
+    defer prune.Stop()
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-prune.C:
+            r.prune(ctx)
+        case <-ticker.C:
+            // Keep draining while batches come back full
+            for {
+                n, err := r.relayBatch(ctx)
+                if err != nil {
+                    if ctx.Err() == nil {
+                        r.logger.Error("Failed to relay outbox events", zap.Error(err))
+                    }
+                    break
+                }
+                if n < int(r.config.BatchSize) {
+                    break
+                }
+            }
+        }
+    }
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+    rows, err := r.queries.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+        LeaseSeconds: int32(r.config.Lease / time.Second),
+        RowLimit:     r.config.BatchSize,
+    })
+    if err != nil {
+        return 0, err
+    }
+    
+    // Stop at the end of the lease; the remaining rows become claimable
+    // again and are retried without counting an attempt
+    leaseCtx, cancel := context.WithTimeout(ctx, r.config.Lease)
+    defer cancel()
+    
+    published := make([]int64, 0, len(rows))
+    for _, row := range rows {
+        if leaseCtx.Err() != nil {
+            break
+        }
+        if err := r.publish(leaseCtx, row); err != nil {
+            if leaseCtx.Err() != nil {
+                break
+            }
+            if err := r.fail(ctx, row, err); err != nil {
+                return 0, err
+            }
+            continue
+        }
+        published = append(published, row.ID)
+    }
+    
+    if len(published) > 0 {
+        if err := r.queries.MarkOutboxEventsPublished(ctx, published); err != nil {
+            return 0, err
+        }
+    }
+    return len(rows), nil
+}
+
+// fail schedules the next attempt for row, or marks it dead once
+// MaxAttempts is reached so it stops blocking the user's later events.
+func (r *Relay) fail(ctx context.Context, row db.Outbox, cause error) error {
+    attempt := int(row.Attempts) + 1
+    dead := attempt >= r.config.MaxAttempts
+    
+    if err := r.queries.RecordOutboxFailure(ctx, db.RecordOutboxFailureParams{
+        ID:            row.ID,
+        LastError:     sql.NullString{String: cause.Error(), Valid: true},
+        NextAttemptAt: time.Now().Add(r.backoff(attempt)),
+        Dead:          dead,
+    }); err != nil {
+        return err
+    }
+    
+    fields := []zap.Field{
+        zap.Error(cause),
+        zap.Int64("outbox_id", row.ID),
+        zap.Int32("user_id", row.AggregateID),
+        zap.Int("attempt", attempt),
+    }
+    if dead {
+        r.logger.Error("Outbox event is dead after too many attempts", fields...)
+    } else {
+        r.logger.Warn("Failed to publish outbox event", fields...)
+    }
+    return nil
+}
+
+// backoff doubles the delay for every attempt, capped at MaxBackoff, with up
+// to 20% jitter so retries from many relays do not line up.
+func (r *Relay) backoff(attempt int) time.Duration {
+    delay := r.config.BaseBackoff
+    for i := 1; i < attempt && delay < r.config.MaxBackoff; i++ {
+        delay *= 2
+    }
+    if delay > r.config.MaxBackoff {
+        delay = r.config.MaxBackoff
+    }
+    return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+func (r *Relay) publish(ctx context.Context, row db.Outbox) error {
+    var event events.Event
+    if err := json.Unmarshal(row.Payload, &event); err != nil {
+        return err
+    }
+    event.ID = row.ID
+    return r.publisher.Publish(ctx, event)
+}
+
+func (r *Relay) prune(ctx context.Context) {
+    deleted, err := r.queries.DeletePublishedOutboxEvents(ctx, time.Now().Add(-r.config.Retention))
+    if err != nil {
+        r.logger.Error("Failed to prune outbox", zap.Error(err))
+        return
+    }
+    if deleted > 0 {
+        r.logger.Info("Pruned published outbox events", zap.Int64("count", deleted))
+    }
+}
@@ -0,0 +1,229 @@
+package outbox
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "os"
+    "slices"
+    "strconv"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/migrations"
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+// fakePublisher fails the events in failing, and with block set waits for
+// ctx to end instead of publishing.
+type fakePublisher struct {
+    failing   []int64
+    block     bool
+    published []int64
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event events.Event) error {
+    if p.block {
+        <-ctx.Done()
+        return ctx.Err()
+    }
+    if slices.Contains(p.failing, event.ID) {
+        return errors.New("broker unavailable")
+    }
+    p.published = append(p.published, event.ID)
+    return nil
+}
+
+func TestBackoff(t *testing.T) {
+    r := NewRelay(nil, nil, Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}, zap.NewNop())
+    
+    tests := []struct {
+        attempt int
+        want    time.Duration
+    }{
+        {1, time.Second},
+        {2, 2 * time.Second},
+        {4, 8 * time.Second},
+        {5, 10 * time.Second},
+        {30, 10 * time.Second},
+    }
+    
+    for _, tt := range tests {
+        t.Run(strconv.Itoa(tt.attempt), func(t *testing.T) {
+            // Jitter adds up to 20%
+            for range 50 {
+                got := r.backoff(tt.attempt)
+                if got < tt.want || got > tt.want+tt.want/5 {
+                    t.Fatalf("backoff(%d) = %v, want %v plus up to 20%%", tt.attempt, got, tt.want)
+                }
+            }
+        })
+    }
+}
+
+// outboxRow is the state relayBatch leaves behind. delayed is set while
+// next_attempt_at is in the future, as after a claim or a failure.
+type outboxRow struct {
+    attempts  int32
+    lastError bool
+    published bool
+    dead      bool
+    delayed   bool
+}
+
+// TestRelayBatch needs Postgres for the claim query, so it runs only when
+// TEST_DATABASE_URL points at a disposable database; its outbox table is
+// emptied before every case.
+func TestRelayBatch(t *testing.T) {
+    dsn := os.Getenv("TEST_DATABASE_URL")
+    if dsn == "" {
+        t.Skip("TEST_DATABASE_URL is not set")
+    }
+    conn, _, err := database.Open(dsn, 0)
+    if err != nil {
+        t.Fatalf("open postgres: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    if err := database.Migrate(conn, migrations.FS); err != nil {
+        t.Fatalf("migrate postgres: %v", err)
+    }
+    
+    tests := []struct {
+        name      string
+        publisher *fakePublisher
+        // setup runs after the events for users 1, 1 and 2 are inserted
+        setup string
+        // retry, when set, relays a second batch after the first
+        retry         *fakePublisher
+        wantPublished []int64
+        want          []outboxRow
+    }{
+        {
+            name:          "oldest event per user is published",
+            publisher:     &fakePublisher{},
+            wantPublished: []int64{1, 3},
+            want: []outboxRow{
+                {published: true, delayed: true},
+                {},
+                {published: true, delayed: true},
+            },
+        },
+        {
+            name:          "failure is retried later",
+            publisher:     &fakePublisher{failing: []int64{1}},
+            wantPublished: []int64{3},
+            want: []outboxRow{
+                {attempts: 1, lastError: true, delayed: true},
+                {},
+                {published: true, delayed: true},
+            },
+        },
+        {
+            name:          "last attempt is dead-lettered",
+            publisher:     &fakePublisher{failing: []int64{1}},
+            setup:         `UPDATE outbox SET attempts = 2 WHERE id = 1`,
+            wantPublished: []int64{3},
+            want: []outboxRow{
+                {attempts: 3, lastError: true, dead: true, delayed: true},
+                {},
+                {published: true, delayed: true},
+            },
+        },
+        {
+            name:          "dead event unblocks the next",
+            publisher:     &fakePublisher{},
+            setup:         `UPDATE outbox SET dead_at = NOW() WHERE id = 1`,
+            wantPublished: []int64{2, 3},
+            want: []outboxRow{
+                {dead: true},
+                {published: true, delayed: true},
+                {published: true, delayed: true},
+            },
+        },
+        {
+            name:          "expired lease leaves rows for a retry",
+            publisher:     &fakePublisher{block: true},
+            retry:         &fakePublisher{},
+            wantPublished: []int64{1, 3},
+            want: []outboxRow{
+                {published: true, delayed: true},
+                {},
+                {published: true, delayed: true},
+            },
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            if _, err := conn.Exec(`TRUNCATE outbox RESTART IDENTITY`); err != nil {
+                t.Fatalf("reset outbox: %v", err)
+            }
+            queries := db.New(conn)
+            for _, userID := range []int32{1, 1, 2} {
+                if _, err := queries.InsertOutboxEvent(ctx, db.InsertOutboxEventParams{
+                    AggregateID: userID,
+                    EventType:   string(events.UserUpdated),
+                    Payload:     []byte(`{"type":"user.updated","user_id":` + strconv.Itoa(int(userID)) + `}`),
+                }); err != nil {
+                    t.Fatalf("insert outbox event: %v", err)
+                }
+            }
+            if tt.setup != "" {
+                if _, err := conn.Exec(tt.setup); err != nil {
+                    t.Fatalf("setup: %v", err)
+                }
+            }
+            
+            config := DefaultConfig()
+            config.Lease = time.Second
+            config.MaxAttempts = 3
+            if _, err := NewRelay(conn, tt.publisher, config, zap.NewNop()).relayBatch(ctx); err != nil {
+                t.Fatalf("relayBatch: %v", err)
+            }
+            published := tt.publisher.published
+            if tt.retry != nil {
+                // The first batch only returns once its lease has run out
+                if _, err := NewRelay(conn, tt.retry, config, zap.NewNop()).relayBatch(ctx); err != nil {
+                    t.Fatalf("second relayBatch: %v", err)
+                }
+                published = append(published, tt.retry.published...)
+            }
+            
+            slices.Sort(published)
+            if !slices.Equal(published, tt.wantPublished) {
+                t.Errorf("published %v, want %v", published, tt.wantPublished)
+            }
+            if got := outboxRows(t, conn); !slices.Equal(got, tt.want) {
+                t.Errorf("outbox rows = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+func outboxRows(t *testing.T, conn *sql.DB) []outboxRow {
+    t.Helper()
+    rows, err := conn.Query(`
+        SELECT attempts, last_error IS NOT NULL, published_at IS NOT NULL, dead_at IS NOT NULL, next_attempt_at > NOW()
+        FROM outbox ORDER BY id`)
+    if err != nil {
+        t.Fatalf("query outbox: %v", err)
+    }
+    defer rows.Close()
+    var result []outboxRow
+    for rows.Next() {
+        var row outboxRow
+        if err := rows.Scan(&row.attempts, &row.lastError, &row.published, &row.dead, &row.delayed); err != nil {
+            t.Fatalf("scan outbox: %v", err)
+        }
+        result = append(result, row)
+    }
+    if err := rows.Err(); err != nil {
+        t.Fatalf("query outbox: %v", err)
+    }
+    return result
+}
@@ -0,0 +1,76 @@
+package pgnotify
+
+import (
+    "context"
+    "database/sql"
+    "time"
+    
+    "github.com/lib/pq"
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+)
+
+const (
+    minReconnectInterval = 100 * time.Millisecond
+    maxReconnectInterval = 10 * time.Second
+    pingInterval         = 90 * time.Second
+)
+
+type Notifier struct {
+    queries *db.Queries
+}
+
+func NewNotifier(database *sql.DB) *Notifier {
+    return &Notifier{queries: db.New(database)}
+}
+
+// Notify sends payload on channel. Postgres limits payloads to 8000 bytes.
+func (n *Notifier) Notify(ctx context.Context, channel, payload string) error {
+    return n.queries.Notify(ctx, db.NotifyParams{
+        Channel: channel,
+        Payload: payload,
+    })
+}
+
+// Listen delivers notifications on channel to handle until ctx is
+// cancelled, reconnecting as needed. onReconnect is called after the
+// connection is re-established, since notifications sent while it was down
+// are lost.
+func Listen(ctx context.Context, dsn, channel string, handle func(payload string), onReconnect func(), logger *zap.Logger) error {
+    listener := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+        if err != nil {
+            logger.Warn("Postgres listener error", zap.String("channel", channel), zap.Error(err))
+        }
+    })
+    defer listener.Close()
+    
+    if err := listener.Listen(channel); err != nil {
+        return err
+    }
+    
+    ticker := time.NewTicker(pingInterval)
+    defer ticker.Stop()
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case notification := <-listener.Notify:
+            // A nil notification means the connection was re-established
+            if notification == nil {
+                if onReconnect != nil {
+                    onReconnect()
+                }
+                continue
+            }
+            handle(notification.Extra)
+        case <-ticker.C:
+            go func() {
+                if err := listener.Ping(); err != nil {
+                    logger.Warn("Postgres listener ping failed", zap.String("channel", channel), zap.Error(err))
+                }
+            }()
+        }
+    }
+}
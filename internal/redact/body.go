@@ -0,0 +1,116 @@
+package redact
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/url"
+    "strings"
+)
+
+// Body prepares a captured request or response body for logging. In JSON
+// bodies PII keys are masked and every string goes through Message, which
+// also covers values inlined into GraphQL queries; form bodies have PII keys
+// masked. Anything else is omitted, since it cannot be inspected. The
+// result is cut to limit bytes.
+func Body(contentType string, body []byte, limit int) string {
+    if len(body) == 0 {
+        return ""
+    }
+    
+    var result string
+    switch {
+    case strings.Contains(contentType, "json") || json.Valid(bytes.TrimSpace(body)):
+        masked, err := JSON(body)
+        if err != nil {
+            return fmt.Sprintf("[%d bytes of invalid JSON omitted]", len(body))
+        }
+        result = string(masked)
+    case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+        values, err := url.ParseQuery(string(body))
+        if err != nil {
+            return fmt.Sprintf("[%d bytes of invalid form data omitted]", len(body))
+        }
+        for key := range values {
+            if Key(key) {
+                values[key] = []string{Mask}
+            }
+        }
+        result = values.Encode()
+    default:
+        return fmt.Sprintf("[%d bytes omitted]", len(body))
+    }
+    
+    if limit > 0 && len(result) > limit {
+        result = result[:limit] + "...(truncated)"
+    }
+    return result
+}
+
+// URI prepares a request URI for logging. Query parameters named after PII
+// keys are masked; other values go through JSON when they parse as JSON and
+// Message otherwise, which covers GraphQL queries and variables sent with
+// GET.
+func URI(uri string) string {
+    path, rawQuery, found := strings.Cut(uri, "?")
+    if !found || rawQuery == "" {
+        return uri
+    }
+    values, err := url.ParseQuery(rawQuery)
+    if err != nil {
+        return path + "?" + Mask
+    }
+    for key, list := range values {
+        for i, value := range list {
+            list[i] = queryValue(key, value)
+        }
+    }
+    return path + "?" + values.Encode()
+}
+
+func queryValue(key, value string) string {
+    if Key(key) {
+        return Mask
+    }
+    if json.Valid([]byte(value)) {
+        if masked, err := JSON([]byte(value)); err == nil {
+            return string(masked)
+        }
+    }
+    return Message(value)
+}
+
+// JSON returns body with the values of PII keys masked and Message applied
+// to all other strings.
+func JSON(body []byte) ([]byte, error) {
+    decoder := json.NewDecoder(bytes.NewReader(body))
+    decoder.UseNumber()
+    var document any
+    if err := decoder.Decode(&document); err != nil {
+        return nil, err
+    }
+    return json.Marshal(maskJSON(document))
+}
+
+func maskJSON(v any) any {
+    switch v := v.(type) {
+    case map[string]any:
+        for key, value := range v {
+            if Key(key) {
+                v[key] = Mask
+                continue
+            }
+            v[key] = maskJSON(value)
+        }
+        return v
+    case []any:
+        for i, value := range v {
+            v[i] = maskJSON(value)
+        }
+        return v
+    case string:
+        return Message(v)
+    default:
+        return v
+    }
+}
@@ -0,0 +1,58 @@
+package redact
+
+import (
+    "testing"
+)
+
+func TestBody(t *testing.T) {
+    tests := []struct {
+        name        string
+        contentType string
+        body        string
+        limit       int
+        want        string
+    }{
+        {"empty", "application/json", "", 0, ""},
+        {"JSON keys", "application/json", `{"id":1,"passport_no":"X123","nested":{"Email":"a@example.com"}}`, 0, `{"id":1,"nested":{"Email":"[REDACTED]"},"passport_no":"[REDACTED]"}`},
+        {"JSON strings", "application/json", `{"query":"{ users(filter: {name: \"Jane\"}) { id } }"}`, 0, `{"query":"{ users(filter: {name: [REDACTED]}) { id } }"}`},
+        {"JSON arrays and numbers", "application/json", `[{"email":"a@example.com","score":1.50}]`, 0, `[{"email":"[REDACTED]","score":1.50}]`},
+        {"JSON without content type", "", `{"email":"a@example.com"}`, 0, `{"email":"[REDACTED]"}`},
+        {"invalid JSON", "application/json", `{"email":`, 0, "[9 bytes of invalid JSON omitted]"},
+        {"form", "application/x-www-form-urlencoded", "email=a%40example.com&plan=pro", 0, "email=%5BREDACTED%5D&plan=pro"},
+        {"invalid form", "application/x-www-form-urlencoded", "a=%zz", 0, "[5 bytes of invalid form data omitted]"},
+        {"other content", "text/plain", "Jane Doe", 0, "[8 bytes omitted]"},
+        {"truncated", "application/json", `{"plan":"enterprise"}`, 10, `{"plan":"e...(truncated)`},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Body(tt.contentType, []byte(tt.body), tt.limit); got != tt.want {
+                t.Errorf("Body() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestURI(t *testing.T) {
+    tests := []struct {
+        name string
+        uri  string
+        want string
+    }{
+        {"no query", "/users/42", "/users/42"},
+        {"empty query", "/users?", "/users?"},
+        {"PII key", "/users?email=a%40example.com&limit=10", "/users?email=%5BREDACTED%5D&limit=10"},
+        {"GraphQL query", `/graphql?query=%7Busers(filter%3A%7Bname%3A%22Jane%22%7D)%7Bid%7D%7D`, "/graphql?query=%7Busers%28filter%3A%7Bname%3A%5BREDACTED%5D%7D%29%7Bid%7D%7D"},
+        {"GraphQL variables", `/graphql?variables=%7B%22email%22%3A%22a%40example.com%22%2C%22dob%22%3A%221990-01-02%22%7D`, "/graphql?variables=%7B%22dob%22%3A%22%5BREDACTED%5D%22%2C%22email%22%3A%22%5BREDACTED%5D%22%7D"},
+        {"date value", "/users?since=1990-01-02", "/users?since=%5BREDACTED%5D"},
+        {"invalid query", "/users?a=%zz", "/users?[REDACTED]"},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := URI(tt.uri); got != tt.want {
+                t.Errorf("URI(%q) = %q, want %q", tt.uri, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,224 @@
+// Package redact masks personal data before it reaches logs, error
+// responses or captured request bodies.
+//
+// Struct fields holding personal data are tagged `pii:"true"` and their
+// types registered with Register. Their JSON names then also mark personal
+// data wherever they appear as a key: log fields, JSON bodies and maps.
+package redact
+
+import (
+    "fmt"
+    "reflect"
+    "regexp"
+    "strings"
+    "sync"
+)
+
+// Mask replaces every redacted value.
+const Mask = "[REDACTED]"
+
+var (
+    mu sync.RWMutex
+    // fields holds the PII field names of each registered struct type
+    fields = make(map[reflect.Type]map[string]bool)
+    // keys holds the lower-cased JSON names of all PII fields
+    keys = make(map[string]bool)
+    
+    // containsCache remembers whether values of a type can hold PII
+    containsCache sync.Map
+)
+
+// Register records the PII fields of v's struct type: those tagged
+// `pii:"true"` plus any listed in names, for types such as generated code
+// that cannot carry tags.
+func Register(v any, names ...string) {
+    t := reflect.TypeOf(v)
+    for t.Kind() == reflect.Pointer {
+        t = t.Elem()
+    }
+    if t.Kind() != reflect.Struct {
+        panic(fmt.Sprintf("redact: Register of non-struct type %s", t))
+    }
+    
+    pii := make(map[string]bool)
+    for _, name := range names {
+        if _, ok := t.FieldByName(name); !ok {
+            panic(fmt.Sprintf("redact: %s has no field %s", t, name))
+        }
+        pii[name] = true
+    }
+    for i := 0; i < t.NumField(); i++ {
+        if t.Field(i).Tag.Get("pii") == "true" {
+            pii[t.Field(i).Name] = true
+        }
+    }
+    
+    mu.Lock()
+    defer mu.Unlock()
+    fields[t] = pii
+    for name := range pii {
+        field, _ := t.FieldByName(name)
+        keys[strings.ToLower(jsonName(field))] = true
+    }
+    containsCache.Clear()
+}
+
+// Key reports whether key names personal data, e.g. "name" or "dob".
+func Key(key string) bool {
+    mu.RLock()
+    defer mu.RUnlock()
+    return keys[strings.ToLower(key)]
+}
+
+var (
+    quotedPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+    datePattern   = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`)
+)
+
+// Message masks the parts of a free-form message most likely to echo input:
+// quoted values, as in database and parse errors, and dates.
+func Message(s string) string {
+    s = quotedPattern.ReplaceAllString(s, Mask)
+    return datePattern.ReplaceAllString(s, Mask)
+}
+
+// Error returns err's message with Message applied.
+func Error(err error) string {
+    if err == nil {
+        return ""
+    }
+    return Message(err.Error())
+}
+
+// Recovered describes a recovered panic value for logging.
+func Recovered(r any) string {
+    switch r := r.(type) {
+    case error:
+        return Error(r)
+    case string:
+        return Message(r)
+    default:
+        return Message(fmt.Sprintf("%v", Value(r)))
+    }
+}
+
+// Value returns v unchanged when its type cannot hold PII, and otherwise a
+// copy built from maps and slices, keyed like its JSON form, with PII fields
+// masked.
+func Value(v any) any {
+    if v == nil {
+        return nil
+    }
+    rv := reflect.ValueOf(v)
+    if !containsPII(rv.Type()) {
+        return v
+    }
+    return mask(rv)
+}
+
+func mask(v reflect.Value) any {
+    switch v.Kind() {
+    case reflect.Pointer, reflect.Interface:
+        if v.IsNil() {
+            return nil
+        }
+        return Value(v.Elem().Interface())
+    case reflect.Struct:
+        mu.RLock()
+        pii := fields[v.Type()]
+        mu.RUnlock()
+        
+        result := make(map[string]any)
+        for i := 0; i < v.NumField(); i++ {
+            field := v.Type().Field(i)
+            name := jsonName(field)
+            if !field.IsExported() || name == "-" {
+                continue
+            }
+            if pii[field.Name] {
+                result[name] = Mask
+                continue
+            }
+            result[name] = Value(v.Field(i).Interface())
+        }
+        return result
+    case reflect.Slice, reflect.Array:
+        if v.Kind() == reflect.Slice && v.IsNil() {
+            return nil
+        }
+        result := make([]any, v.Len())
+        for i := range result {
+            result[i] = Value(v.Index(i).Interface())
+        }
+        return result
+    case reflect.Map:
+        if v.IsNil() {
+            return nil
+        }
+        result := make(map[string]any, v.Len())
+        iter := v.MapRange()
+        for iter.Next() {
+            key := fmt.Sprint(iter.Key().Interface())
+            if Key(key) {
+                result[key] = Mask
+                continue
+            }
+            result[key] = Value(iter.Value().Interface())
+        }
+        return result
+    default:
+        return v.Interface()
+    }
+}
+
+// containsPII reports whether a value of type t can hold PII: registered
+// structs, containers of them, string-keyed maps and interfaces, whose
+// dynamic content is only known at run time.
+func containsPII(t reflect.Type) bool {
+    if cached, ok := containsCache.Load(t); ok {
+        return cached.(bool)
+    }
+    result := inspect(t, make(map[reflect.Type]bool))
+    containsCache.Store(t, result)
+    return result
+}
+
+// inspect does the work of containsPII. Types already being inspected
+// further up a recursive type count as clean, since whatever they hold is
+// found on that outer visit.
+func inspect(t reflect.Type, visiting map[reflect.Type]bool) bool {
+    if visiting[t] {
+        return false
+    }
+    visiting[t] = true
+    
+    switch t.Kind() {
+    case reflect.Interface:
+        return true
+    case reflect.Pointer, reflect.Slice, reflect.Array:
+        return inspect(t.Elem(), visiting)
+    case reflect.Map:
+        return t.Key().Kind() == reflect.String || inspect(t.Elem(), visiting)
+    case reflect.Struct:
+        mu.RLock()
+        registered := len(fields[t]) > 0
+        mu.RUnlock()
+        if registered {
+            return true
+        }
+        for i := 0; i < t.NumField(); i++ {
+            if t.Field(i).IsExported() && inspect(t.Field(i).Type, visiting) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func jsonName(field reflect.StructField) string {
+    name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+    if name == "" {
+        return field.Name
+    }
+    return name
+}
@@ -0,0 +1,138 @@
+package redact
+
+import (
+    "reflect"
+    "testing"
+)
+
+type taggedRecord struct {
+    ID       int    `json:"id"`
+    Passport string `json:"passport_no" pii:"true"`
+    Nickname string
+    internal string
+}
+
+type namedRecord struct {
+    Email string
+    Notes string `json:"notes"`
+}
+
+type wrapper struct {
+    Record  *taggedRecord  `json:"record"`
+    Records []namedRecord  `json:"records"`
+    Extra   map[string]any `json:"extra"`
+}
+
+func init() {
+    Register(taggedRecord{})
+    Register(&namedRecord{}, "Email")
+}
+
+func TestRegisterPanics(t *testing.T) {
+    tests := []struct {
+        name  string
+        value any
+        names []string
+    }{
+        {"non-struct", "text", nil},
+        {"unknown field", namedRecord{}, []string{"Missing"}},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            defer func() {
+                if recover() == nil {
+                    t.Error("Register did not panic")
+                }
+            }()
+            Register(tt.value, tt.names...)
+        })
+    }
+}
+
+func TestKey(t *testing.T) {
+    tests := []struct {
+        key  string
+        want bool
+    }{
+        {"passport_no", true},
+        {"PASSPORT_NO", true},
+        {"Email", true},
+        {"email", true},
+        {"id", false},
+        {"notes", false},
+        {"Nickname", false},
+        {"Passport", false},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.key, func(t *testing.T) {
+            if got := Key(tt.key); got != tt.want {
+                t.Errorf("Key(%q) = %v, want %v", tt.key, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestValue(t *testing.T) {
+    tests := []struct {
+        name  string
+        value any
+        want  any
+    }{
+        {"nil", nil, nil},
+        {"clean type unchanged", 42, 42},
+        {
+            name:  "tagged field",
+            value: taggedRecord{ID: 1, Passport: "X123", Nickname: "ace", internal: "hidden"},
+            want:  map[string]any{"id": 1, "passport_no": Mask, "Nickname": "ace"},
+        },
+        {
+            name:  "named field through a pointer",
+            value: &namedRecord{Email: "a@example.com", Notes: "n"},
+            want:  map[string]any{"Email": Mask, "notes": "n"},
+        },
+        {
+            name: "nested",
+            value: wrapper{
+                Record:  &taggedRecord{ID: 2, Passport: "Y456"},
+                Records: []namedRecord{{Email: "b@example.com"}},
+                Extra:   map[string]any{"email": "c@example.com", "plan": "pro"},
+            },
+            want: map[string]any{
+                "record":  map[string]any{"id": 2, "passport_no": Mask, "Nickname": ""},
+                "records": []any{map[string]any{"Email": Mask, "notes": ""}},
+                "extra":   map[string]any{"email": Mask, "plan": "pro"},
+            },
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Value(tt.value); !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("Value() = %#v, want %#v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestMessage(t *testing.T) {
+    tests := []struct {
+        in   string
+        want string
+    }{
+        {`duplicate key "Jane Doe"`, `duplicate key ` + Mask},
+        {`parsing time "1990-13-01": month out of range`, `parsing time ` + Mask + `: month out of range`},
+        {"born 1990-01-02 in town", "born " + Mask + " in town"},
+        {`escaped "a \"quoted\" name" here`, `escaped ` + Mask + ` here`},
+        {"nothing to hide", "nothing to hide"},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.in, func(t *testing.T) {
+            if got := Message(tt.in); got != tt.want {
+                t.Errorf("Message(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+    "context"
+    "strconv"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/pgnotify"
+)
+
+const CacheInvalidationChannel = "user_cache_invalidation"
+
+// NotifyInvalidator broadcasts cache invalidations to every replica through
+// Postgres NOTIFY. Each replica runs ListenForInvalidations.
+type NotifyInvalidator struct {
+    notifier *pgnotify.Notifier
+}
+
+func NewNotifyInvalidator(notifier *pgnotify.Notifier) *NotifyInvalidator {
+    return &NotifyInvalidator{notifier: notifier}
+}
+
+func (i *NotifyInvalidator) Invalidate(ctx context.Context, id int32) error {
+    return i.notifier.Notify(ctx, CacheInvalidationChannel, strconv.Itoa(int(id)))
+}
+
+// ListenForInvalidations evicts users named on CacheInvalidationChannel from
+// cached until ctx is cancelled. The whole cache is purged after a
+// reconnect, since invalidations sent while disconnected are lost.
+func ListenForInvalidations(ctx context.Context, dsn string, cached *CachedUserRepository, logger *zap.Logger) error {
+    handle := func(payload string) {
+        id, err := strconv.ParseInt(payload, 10, 32)
+        if err != nil {
+            logger.Error("Invalid user cache invalidation", zap.String("payload", payload))
+            return
+        }
+        cached.Evict(int32(id))
+    }
+    onReconnect := func() {
+        logger.Warn("User cache listener reconnected, purging cache")
+        cached.Purge()
+    }
+    return pgnotify.Listen(ctx, dsn, CacheInvalidationChannel, handle, onReconnect, logger)
+}
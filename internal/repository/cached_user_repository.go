@@ -0,0 +1,158 @@
+package repository
+
+import (
+    "context"
+    "strconv"
+    "sync/atomic"
+    "time"
+    
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "go.uber.org/zap"
+    "golang.org/x/sync/singleflight"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/cache"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+)
+
+var (
+    userCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+        Name: "user_cache_requests_total",
+        Help: "User cache lookups by result (hit or miss).",
+    }, []string{"result"})
+    userCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+        Name: "user_cache_evictions_total",
+        Help: "Users evicted from the cache to make room.",
+    })
+    userCacheInvalidations = promauto.NewCounterVec(prometheus.CounterOpts{
+        Name: "user_cache_invalidations_total",
+        Help: "User cache invalidations by source (local or remote).",
+    }, []string{"source"})
+    userCacheHits   = userCacheRequests.WithLabelValues("hit")
+    userCacheMisses = userCacheRequests.WithLabelValues("miss")
+)
+
+type CacheConfig struct {
+    Size int
+    TTL  time.Duration
+}
+
+// CacheInvalidator tells other replicas that a cached user is stale.
+type CacheInvalidator interface {
+    Invalidate(ctx context.Context, id int32) error
+}
+
+// CachedUserRepository serves GetByID from a bounded in-memory cache and
+// passes everything else through to the wrapped repository. Concurrent
+// misses for the same ID share a single query.
+type CachedUserRepository struct {
+    UserRepository
+    
+    users  *cache.LRU[int32, db.User]
+    group  singleflight.Group
+    peers  CacheInvalidator
+    logger *zap.Logger
+    
+    // generation is bumped on every invalidation so a load that raced with
+    // a write does not cache the value it read before the write
+    generation atomic.Uint64
+}
+
+// NewCachedUserRepository wraps next. peers may be nil when there is only a
+// single replica.
+func NewCachedUserRepository(next UserRepository, config CacheConfig, peers CacheInvalidator, logger *zap.Logger) *CachedUserRepository {
+    return &CachedUserRepository{
+        UserRepository: next,
+        users: cache.NewLRU[int32, db.User](config.Size, config.TTL, func(int32) {
+            userCacheEvictions.Inc()
+        }),
+        peers:  peers,
+        logger: logger,
+    }
+}
+
+func (r *CachedUserRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
+    // A transaction may already have changed the row, so it reads through
+    if _, ok := database.TxFromContext(ctx); ok {
+        return r.UserRepository.GetByID(ctx, id)
+    }
+    
+    if user, ok := r.users.Get(id); ok {
+        userCacheHits.Inc()
+        return &user, nil
+    }
+    userCacheMisses.Inc()
+    
+    generation := r.generation.Load()
+    result, err, _ := r.group.Do(strconv.Itoa(int(id)), func() (interface{}, error) {
+        // The shared load must not fail because the first caller went away
+        user, err := r.UserRepository.GetByID(context.WithoutCancel(ctx), id)
+        if err != nil {
+            return nil, err
+        }
+        if r.generation.Load() == generation {
+            r.users.Add(id, *user)
+        }
+        return *user, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    
+    user := result.(db.User)
+    return &user, nil
+}
+
+func (r *CachedUserRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error) {
+    user, err := r.UserRepository.Update(ctx, id, name, dob)
+    r.invalidate(ctx, id)
+    return user, err
+}
+
+func (r *CachedUserRepository) Delete(ctx context.Context, id int32) error {
+    err := r.UserRepository.Delete(ctx, id)
+    r.invalidate(ctx, id)
+    return err
+}
+
+// Evict drops a single user, for invalidations received from other replicas.
+func (r *CachedUserRepository) Evict(id int32) {
+    r.remove(id)
+    userCacheInvalidations.WithLabelValues("remote").Inc()
+}
+
+// Purge drops every user, for when invalidations may have been missed.
+func (r *CachedUserRepository) Purge() {
+    r.generation.Add(1)
+    r.users.Purge()
+}
+
+// invalidate runs even when the write failed, since the failure may have
+// happened after the row changed. Inside a transaction the entry is dropped
+// again once it commits, because other readers may cache the old row until
+// then.
+func (r *CachedUserRepository) invalidate(ctx context.Context, id int32) {
+    if _, ok := database.TxFromContext(ctx); ok {
+        r.remove(id)
+    }
+    
+    database.AfterCommit(ctx, func() {
+        r.remove(id)
+        userCacheInvalidations.WithLabelValues("local").Inc()
+        
+        if r.peers == nil {
+            return
+        }
+        if err := r.peers.Invalidate(context.WithoutCancel(ctx), id); err != nil {
+            logger.FromContextOr(ctx, r.logger).Warn("Failed to broadcast user cache invalidation", zap.Int32("id", id), zap.Error(err))
+        }
+    })
+}
+
+func (r *CachedUserRepository) remove(id int32) {
+    r.generation.Add(1)
+    r.group.Forget(strconv.Itoa(int(id)))
+    r.users.Remove(id)
+}
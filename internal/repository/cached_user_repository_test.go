@@ -0,0 +1,313 @@
+package repository_test
+
+import (
+    "context"
+    "errors"
+    "path/filepath"
+    "slices"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+)
+
+// countingRepository counts GetByID calls that reach it. When loaded is
+// set, each call reads the row, signals loaded and waits for release before
+// returning, so a write can slip in between.
+type countingRepository struct {
+    repository.UserRepository
+    loads    atomic.Int32
+    loaded   chan struct{}
+    release  chan struct{}
+    failNext bool
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
+    r.loads.Add(1)
+    user, err := r.UserRepository.GetByID(ctx, id)
+    if r.loaded != nil {
+        r.loaded <- struct{}{}
+        <-r.release
+    }
+    return user, err
+}
+
+func (r *countingRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error) {
+    if r.failNext {
+        r.failNext = false
+        // Fail after the change, as a lost commit acknowledgement would
+        _, _ = r.UserRepository.Update(ctx, id, name, dob)
+        return nil, errors.New("connection reset")
+    }
+    return r.UserRepository.Update(ctx, id, name, dob)
+}
+
+// recordingInvalidator remembers the IDs broadcast to other replicas.
+type recordingInvalidator struct {
+    mu  sync.Mutex
+    ids []int32
+}
+
+func (i *recordingInvalidator) Invalidate(ctx context.Context, id int32) error {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+    i.ids = append(i.ids, id)
+    return nil
+}
+
+func (i *recordingInvalidator) broadcast() []int32 {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+    return slices.Clone(i.ids)
+}
+
+func newCachedRepository(t *testing.T) (*repository.CachedUserRepository, *countingRepository, *recordingInvalidator, int32) {
+    t.Helper()
+    counting := &countingRepository{UserRepository: repository.NewMemoryUserRepository()}
+    user, err := counting.Create(context.Background(), "Ada", time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC))
+    if err != nil {
+        t.Fatalf("create user: %v", err)
+    }
+    peers := &recordingInvalidator{}
+    cached := repository.NewCachedUserRepository(counting, repository.CacheConfig{Size: 10, TTL: time.Minute}, peers, zap.NewNop())
+    return cached, counting, peers, user.ID
+}
+
+func TestCachedUserRepositoryInvalidation(t *testing.T) {
+    dob := time.Date(1991, 3, 4, 0, 0, 0, 0, time.UTC)
+    
+    tests := []struct {
+        name          string
+        change        func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32)
+        wantLoads     int32
+        wantBroadcast []int32
+    }{
+        {
+            name: "reads are cached",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+            },
+            wantLoads: 1,
+        },
+        {
+            name: "update",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                _, _ = r.Update(ctx, id, "Grace", dob)
+            },
+            wantLoads:     2,
+            wantBroadcast: []int32{1},
+        },
+        {
+            name: "failed update",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                counting.failNext = true
+                _, _ = r.Update(ctx, id, "Grace", dob)
+            },
+            wantLoads:     2,
+            wantBroadcast: []int32{1},
+        },
+        {
+            name: "delete",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                _ = r.Delete(ctx, id)
+            },
+            wantLoads:     2,
+            wantBroadcast: []int32{1},
+        },
+        {
+            name: "remote eviction",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                r.Evict(id)
+            },
+            wantLoads: 2,
+        },
+        {
+            name: "purge",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                r.Purge()
+            },
+            wantLoads: 2,
+        },
+        {
+            name: "eviction of another user",
+            change: func(ctx context.Context, r *repository.CachedUserRepository, counting *countingRepository, id int32) {
+                r.Evict(id + 1)
+            },
+            wantLoads: 1,
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            cached, counting, peers, id := newCachedRepository(t)
+            
+            if _, err := cached.GetByID(ctx, id); err != nil {
+                t.Fatalf("GetByID: %v", err)
+            }
+            tt.change(ctx, cached, counting, id)
+            _, _ = cached.GetByID(ctx, id)
+            
+            if got := counting.loads.Load(); got != tt.wantLoads {
+                t.Errorf("loads = %d, want %d", got, tt.wantLoads)
+            }
+            if got := peers.broadcast(); !slices.Equal(got, tt.wantBroadcast) {
+                t.Errorf("broadcast = %v, want %v", got, tt.wantBroadcast)
+            }
+        })
+    }
+}
+
+func TestCachedUserRepositoryInTransaction(t *testing.T) {
+    tests := []struct {
+        name          string
+        fnErr         error
+        wantBroadcast []int32
+    }{
+        {"commit", nil, []int32{1}},
+        {"rollback", errors.New("rolled back"), nil},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            sqliteDB, driver, err := database.Open("sqlite://"+filepath.Join(t.TempDir(), "tx.db"), 0)
+            if err != nil {
+                t.Fatalf("open sqlite: %v", err)
+            }
+            t.Cleanup(func() { sqliteDB.Close() })
+            manager := database.NewTxManager(sqliteDB, driver, 0, 0)
+            
+            cached, counting, peers, id := newCachedRepository(t)
+            if _, err := cached.GetByID(ctx, id); err != nil {
+                t.Fatalf("GetByID: %v", err)
+            }
+            
+            _ = manager.WithinTx(ctx, func(ctx context.Context) error {
+                if _, err := cached.GetByID(ctx, id); err != nil {
+                    t.Fatalf("GetByID in transaction: %v", err)
+                }
+                if got := counting.loads.Load(); got != 2 {
+                    t.Errorf("read inside the transaction was served from the cache")
+                }
+                _, _ = cached.Update(ctx, id, "Grace", time.Date(1991, 3, 4, 0, 0, 0, 0, time.UTC))
+                if got := peers.broadcast(); len(got) != 0 {
+                    t.Errorf("broadcast %v before commit", got)
+                }
+                return tt.fnErr
+            })
+            
+            if got := peers.broadcast(); !slices.Equal(got, tt.wantBroadcast) {
+                t.Errorf("broadcast = %v, want %v", got, tt.wantBroadcast)
+            }
+            // The entry is dropped either way, since the write may have
+            // reached the cache's view before the outcome was known
+            _, _ = cached.GetByID(ctx, id)
+            if got := counting.loads.Load(); got != 3 {
+                t.Errorf("loads = %d, want 3", got)
+            }
+        })
+    }
+}
+
+func TestCachedUserRepositorySharesLoads(t *testing.T) {
+    ctx := context.Background()
+    cached, counting, _, id := newCachedRepository(t)
+    counting.loaded = make(chan struct{})
+    counting.release = make(chan struct{})
+    
+    const callers = 5
+    var wg sync.WaitGroup
+    for range callers {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if _, err := cached.GetByID(ctx, id); err != nil {
+                t.Errorf("GetByID: %v", err)
+            }
+        }()
+    }
+    <-counting.loaded
+    // Give the other callers time to join the load in flight
+    time.Sleep(50 * time.Millisecond)
+    close(counting.release)
+    wg.Wait()
+    
+    if got := counting.loads.Load(); got != 1 {
+        t.Errorf("loads = %d, want 1 shared load", got)
+    }
+}
+
+func TestCachedUserRepositoryLoadRacingWrite(t *testing.T) {
+    tests := []struct {
+        name  string
+        write func(ctx context.Context, r *repository.CachedUserRepository, id int32)
+        want  string
+    }{
+        {
+            name: "update",
+            write: func(ctx context.Context, r *repository.CachedUserRepository, id int32) {
+                _, _ = r.Update(ctx, id, "Grace", time.Date(1991, 3, 4, 0, 0, 0, 0, time.UTC))
+            },
+            want: "Grace",
+        },
+        {
+            name: "remote eviction",
+            write: func(ctx context.Context, r *repository.CachedUserRepository, id int32) {
+                r.Evict(id)
+            },
+            want: "Ada",
+        },
+        {
+            name: "purge",
+            write: func(ctx context.Context, r *repository.CachedUserRepository, id int32) {
+                r.Purge()
+            },
+            want: "Ada",
+        },
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            cached, counting, _, id := newCachedRepository(t)
+            counting.loaded = make(chan struct{})
+            counting.release = make(chan struct{})
+            
+            done := make(chan *db.User)
+            go func() {
+                user, err := cached.GetByID(ctx, id)
+                if err != nil {
+                    t.Errorf("GetByID: %v", err)
+                }
+                done <- user
+            }()
+            
+            // The load has read the old row; the write lands before it
+            // returns
+            <-counting.loaded
+            tt.write(ctx, cached, id)
+            close(counting.release)
+            if stale := <-done; stale.Name != "Ada" {
+                t.Fatalf("racing load returned %q, want the row it read", stale.Name)
+            }
+            
+            counting.loaded = nil
+            user, err := cached.GetByID(ctx, id)
+            if err != nil {
+                t.Fatalf("GetByID: %v", err)
+            }
+            if user.Name != tt.want {
+                t.Errorf("name = %q, want %q", user.Name, tt.want)
+            }
+            if got := counting.loads.Load(); got != 2 {
+                t.Errorf("loads = %d, want 2: the racing load must not be cached", got)
+            }
+        })
+    }
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+)
+
+// memoryUserRepository is a thread-safe UserRepository that mirrors the
+// Postgres implementation: IDs come from a sequence and are never reused,
+// missing rows yield sql.ErrNoRows, and lists are ordered by ID. It does not
+// write outbox events.
+type memoryUserRepository struct {
+    mu     sync.RWMutex
+    users  map[int32]db.User
+    nextID int32
+}
+
+func NewMemoryUserRepository() UserRepository {
+    return &memoryUserRepository{
+        users:  make(map[int32]db.User),
+        nextID: 1,
+    }
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, name string, dob time.Time) (*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    user := db.User{
+        ID:   r.nextID,
+        Name: name,
+        Dob:  truncateDate(dob),
+    }
+    r.nextID++
+    r.users[user.ID] = user
+    return &user, nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    
+    user, ok := r.users[id]
+    if !ok {
+        return nil, sql.ErrNoRows
+    }
+    return &user, nil
+}
+
+func (r *memoryUserRepository) List(ctx context.Context, limit, offset int32) ([]*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    
+    return page(r.sorted(func(db.User) bool { return true }), limit, offset), nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    user, ok := r.users[id]
+    if !ok {
+        return nil, sql.ErrNoRows
+    }
+    user.Name = name
+    user.Dob = truncateDate(dob)
+    r.users[id] = user
+    return &user, nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id int32) error {
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+    
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    
+    // Like DELETE ... WHERE id = $1, deleting a missing row is not an error
+    delete(r.users, id)
+    return nil
+}
+
+func (r *memoryUserRepository) Count(ctx context.Context) (int64, error) {
+    if err := ctx.Err(); err != nil {
+        return 0, err
+    }
+    
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    
+    return int64(len(r.users)), nil
+}
+
+func (r *memoryUserRepository) GetByIDs(ctx context.Context, ids []int32) ([]*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    wanted := make(map[int32]struct{}, len(ids))
+    for _, id := range ids {
+        wanted[id] = struct{}{}
+    }
+    
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    
+    return r.sorted(func(user db.User) bool {
+        _, ok := wanted[user.ID]
+        return ok
+    }), nil
+}
+
+func (r *memoryUserRepository) Search(ctx context.Context, filter UserFilter, afterID, limit int32) ([]*db.User, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    
+    nameContains := strings.ToLower(filter.NameContains)
+    
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    
+    matches := r.sorted(func(user db.User) bool {
+        if user.ID <= afterID {
+            return false
+        }
+        if nameContains != "" && !strings.Contains(strings.ToLower(user.Name), nameContains) {
+            return false
+        }
+        if filter.BornAfter != nil && user.Dob.Before(truncateDate(*filter.BornAfter)) {
+            return false
+        }
+        if filter.BornBefore != nil && user.Dob.After(truncateDate(*filter.BornBefore)) {
+            return false
+        }
+        return true
+    })
+    return page(matches, limit, 0), nil
+}
+
+// sorted returns copies of the matching users ordered by ID. Callers must
+// hold r.mu.
+func (r *memoryUserRepository) sorted(match func(db.User) bool) []*db.User {
+    result := make([]*db.User, 0, len(r.users))
+    for _, user := range r.users {
+        if match(user) {
+            user := user
+            result = append(result, &user)
+        }
+    }
+    sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+    return result
+}
+
+func page(users []*db.User, limit, offset int32) []*db.User {
+    if int(offset) >= len(users) {
+        return users[:0]
+    }
+    users = users[offset:]
+    if limit >= 0 && int(limit) < len(users) {
+        users = users[:limit]
+    }
+    return users
+}
+
+// truncateDate drops the time of day, as storing into a DATE column does.
+func truncateDate(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
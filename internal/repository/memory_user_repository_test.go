@@ -0,0 +1,14 @@
+package repository_test
+
+import (
+    "testing"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+    "github.com/adityaK87/go-backend-assignment/internal/repository/repotest"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+    repotest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+        return repository.NewMemoryUserRepository()
+    })
+}
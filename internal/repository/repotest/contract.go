@@ -0,0 +1,297 @@
+// Package repotest holds the behavioural contract shared by every
+// repository.UserRepository implementation. Implementations run it from
+// their own tests:
+//
+//    func TestMemoryUserRepository(t *testing.T) {
+//        repotest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+//            return repository.NewMemoryUserRepository()
+//        })
+//    }
+package repotest
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+)
+
+// NewRepository must return an empty repository. It is called once per
+// subtest; Postgres-backed factories should truncate the users table and
+// reset its sequence.
+type NewRepository func(t *testing.T) repository.UserRepository
+
+func RunUserRepositoryContract(t *testing.T, newRepo NewRepository) {
+    tests := []struct {
+        name string
+        run  func(t *testing.T, repo repository.UserRepository)
+    }{
+        {"CreateAssignsSequentialIDs", testCreateAssignsSequentialIDs},
+        {"GetByIDMissing", testGetByIDMissing},
+        {"GetByIDReturnsCopy", testGetByIDReturnsCopy},
+        {"UpdateChangesFields", testUpdateChangesFields},
+        {"UpdateMissing", testUpdateMissing},
+        {"DeleteRemovesUser", testDeleteRemovesUser},
+        {"DeleteMissing", testDeleteMissing},
+        {"IDsAreNotReused", testIDsAreNotReused},
+        {"ListOrdersAndPages", testListOrdersAndPages},
+        {"Count", testCount},
+        {"GetByIDs", testGetByIDs},
+        {"SearchFilters", testSearchFilters},
+        {"SearchAfterAndLimit", testSearchAfterAndLimit},
+        {"ConcurrentCreates", testConcurrentCreates},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.run(t, newRepo(t))
+        })
+    }
+}
+
+func date(t *testing.T, value string) time.Time {
+    t.Helper()
+    d, err := time.Parse("2006-01-02", value)
+    if err != nil {
+        t.Fatalf("parse date %q: %v", value, err)
+    }
+    return d
+}
+
+func mustCreate(t *testing.T, repo repository.UserRepository, name, dob string) *db.User {
+    t.Helper()
+    user, err := repo.Create(context.Background(), name, date(t, dob))
+    if err != nil {
+        t.Fatalf("Create(%q): %v", name, err)
+    }
+    return user
+}
+
+func assertUser(t *testing.T, got *db.User, id int32, name, dob string) {
+    t.Helper()
+    if got == nil {
+        t.Fatalf("got nil user, want id %d", id)
+    }
+    if got.ID != id || got.Name != name || got.Dob.Format("2006-01-02") != dob {
+        t.Fatalf("got {%d %q %s}, want {%d %q %s}", got.ID, got.Name, got.Dob.Format("2006-01-02"), id, name, dob)
+    }
+}
+
+func ids(users []*db.User) []int32 {
+    result := make([]int32, len(users))
+    for i, user := range users {
+        result[i] = user.ID
+    }
+    return result
+}
+
+func assertIDs(t *testing.T, got []*db.User, want ...int32) {
+    t.Helper()
+    if fmt.Sprint(ids(got)) != fmt.Sprint(want) {
+        t.Fatalf("got ids %v, want %v", ids(got), want)
+    }
+}
+
+func testCreateAssignsSequentialIDs(t *testing.T, repo repository.UserRepository) {
+    first := mustCreate(t, repo, "Alice", "1990-05-10")
+    second := mustCreate(t, repo, "Bob", "1985-01-02")
+    
+    assertUser(t, first, 1, "Alice", "1990-05-10")
+    assertUser(t, second, 2, "Bob", "1985-01-02")
+}
+
+func testGetByIDMissing(t *testing.T, repo repository.UserRepository) {
+    _, err := repo.GetByID(context.Background(), 42)
+    if !errors.Is(err, sql.ErrNoRows) {
+        t.Fatalf("got %v, want sql.ErrNoRows", err)
+    }
+}
+
+func testGetByIDReturnsCopy(t *testing.T, repo repository.UserRepository) {
+    created := mustCreate(t, repo, "Alice", "1990-05-10")
+    created.Name = "Mallory"
+    
+    got, err := repo.GetByID(context.Background(), created.ID)
+    if err != nil {
+        t.Fatalf("GetByID: %v", err)
+    }
+    assertUser(t, got, created.ID, "Alice", "1990-05-10")
+}
+
+func testUpdateChangesFields(t *testing.T, repo repository.UserRepository) {
+    created := mustCreate(t, repo, "Alice", "1990-05-10")
+    
+    updated, err := repo.Update(context.Background(), created.ID, "Alicia", date(t, "1991-06-11"))
+    if err != nil {
+        t.Fatalf("Update: %v", err)
+    }
+    assertUser(t, updated, created.ID, "Alicia", "1991-06-11")
+    
+    got, err := repo.GetByID(context.Background(), created.ID)
+    if err != nil {
+        t.Fatalf("GetByID: %v", err)
+    }
+    assertUser(t, got, created.ID, "Alicia", "1991-06-11")
+}
+
+func testUpdateMissing(t *testing.T, repo repository.UserRepository) {
+    _, err := repo.Update(context.Background(), 42, "Nobody", date(t, "2000-01-01"))
+    if !errors.Is(err, sql.ErrNoRows) {
+        t.Fatalf("got %v, want sql.ErrNoRows", err)
+    }
+}
+
+func testDeleteRemovesUser(t *testing.T, repo repository.UserRepository) {
+    created := mustCreate(t, repo, "Alice", "1990-05-10")
+    
+    if err := repo.Delete(context.Background(), created.ID); err != nil {
+        t.Fatalf("Delete: %v", err)
+    }
+    if _, err := repo.GetByID(context.Background(), created.ID); !errors.Is(err, sql.ErrNoRows) {
+        t.Fatalf("GetByID after Delete: got %v, want sql.ErrNoRows", err)
+    }
+}
+
+func testDeleteMissing(t *testing.T, repo repository.UserRepository) {
+    if err := repo.Delete(context.Background(), 42); err != nil {
+        t.Fatalf("Delete of missing user: got %v, want nil", err)
+    }
+}
+
+func testIDsAreNotReused(t *testing.T, repo repository.UserRepository) {
+    first := mustCreate(t, repo, "Alice", "1990-05-10")
+    if err := repo.Delete(context.Background(), first.ID); err != nil {
+        t.Fatalf("Delete: %v", err)
+    }
+    
+    second := mustCreate(t, repo, "Bob", "1985-01-02")
+    if second.ID <= first.ID {
+        t.Fatalf("got id %d after deleting %d, want a larger id", second.ID, first.ID)
+    }
+}
+
+func testListOrdersAndPages(t *testing.T, repo repository.UserRepository) {
+    for i := 0; i < 5; i++ {
+        mustCreate(t, repo, fmt.Sprintf("User %d", i), "2000-01-01")
+    }
+    
+    all, err := repo.List(context.Background(), 10, 0)
+    if err != nil {
+        t.Fatalf("List: %v", err)
+    }
+    assertIDs(t, all, 1, 2, 3, 4, 5)
+    
+    page, err := repo.List(context.Background(), 2, 2)
+    if err != nil {
+        t.Fatalf("List: %v", err)
+    }
+    assertIDs(t, page, 3, 4)
+    
+    beyond, err := repo.List(context.Background(), 10, 10)
+    if err != nil {
+        t.Fatalf("List: %v", err)
+    }
+    assertIDs(t, beyond)
+}
+
+func testCount(t *testing.T, repo repository.UserRepository) {
+    count, err := repo.Count(context.Background())
+    if err != nil || count != 0 {
+        t.Fatalf("Count on empty repository: got %d, %v", count, err)
+    }
+    
+    mustCreate(t, repo, "Alice", "1990-05-10")
+    created := mustCreate(t, repo, "Bob", "1985-01-02")
+    if err := repo.Delete(context.Background(), created.ID); err != nil {
+        t.Fatalf("Delete: %v", err)
+    }
+    
+    count, err = repo.Count(context.Background())
+    if err != nil || count != 1 {
+        t.Fatalf("Count: got %d, %v, want 1", count, err)
+    }
+}
+
+func testGetByIDs(t *testing.T, repo repository.UserRepository) {
+    for i := 0; i < 4; i++ {
+        mustCreate(t, repo, fmt.Sprintf("User %d", i), "2000-01-01")
+    }
+    
+    got, err := repo.GetByIDs(context.Background(), []int32{4, 2, 99, 2})
+    if err != nil {
+        t.Fatalf("GetByIDs: %v", err)
+    }
+    assertIDs(t, got, 2, 4)
+}
+
+func testSearchFilters(t *testing.T, repo repository.UserRepository) {
+    mustCreate(t, repo, "Alice Smith", "1990-05-10")
+    mustCreate(t, repo, "Bob Jones", "1985-01-02")
+    mustCreate(t, repo, "alison brown", "2001-12-31")
+    
+    byName, err := repo.Search(context.Background(), repository.UserFilter{NameContains: "ALI"}, 0, 10)
+    if err != nil {
+        t.Fatalf("Search: %v", err)
+    }
+    assertIDs(t, byName, 1, 3)
+    
+    after := date(t, "1985-01-02")
+    before := date(t, "1990-05-10")
+    byDOB, err := repo.Search(context.Background(), repository.UserFilter{BornAfter: &after, BornBefore: &before}, 0, 10)
+    if err != nil {
+        t.Fatalf("Search: %v", err)
+    }
+    assertIDs(t, byDOB, 1, 2)
+}
+
+func testSearchAfterAndLimit(t *testing.T, repo repository.UserRepository) {
+    for i := 0; i < 5; i++ {
+        mustCreate(t, repo, fmt.Sprintf("User %d", i), "2000-01-01")
+    }
+    
+    got, err := repo.Search(context.Background(), repository.UserFilter{}, 2, 2)
+    if err != nil {
+        t.Fatalf("Search: %v", err)
+    }
+    assertIDs(t, got, 3, 4)
+}
+
+func testConcurrentCreates(t *testing.T, repo repository.UserRepository) {
+    const workers = 20
+    
+    var wg sync.WaitGroup
+    results := make(chan int32, workers)
+    for i := 0; i < workers; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            user, err := repo.Create(context.Background(), fmt.Sprintf("User %d", i), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+            if err != nil {
+                t.Errorf("Create: %v", err)
+                return
+            }
+            results <- user.ID
+        }(i)
+    }
+    wg.Wait()
+    close(results)
+    
+    seen := make(map[int32]bool)
+    for id := range results {
+        if seen[id] {
+            t.Fatalf("id %d assigned twice", id)
+        }
+        seen[id] = true
+    }
+    
+    count, err := repo.Count(context.Background())
+    if err != nil || count != workers {
+        t.Fatalf("Count: got %d, %v, want %d", count, err, workers)
+    }
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "sync/atomic"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/db/sqlite/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+)
+
+// sqliteUserRepository backs single-node deployments. There is no outbox or
+// relay: events are published to the in-process publisher once the write has
+// committed, including the enclosing database.TxManager transaction if any.
+// Event IDs are seeded from the clock so they keep increasing across
+// restarts, which SSE clients rely on when resuming.
+type sqliteUserRepository struct {
+    queries   *sqlitedb.Queries
+    publisher events.Publisher
+    eventID   atomic.Int64
+}
+
+func NewSQLiteUserRepository(database *sql.DB, publisher events.Publisher) UserRepository {
+    r := &sqliteUserRepository{
+        queries:   sqlitedb.New(database),
+        publisher: publisher,
+    }
+    r.eventID.Store(time.Now().UnixMicro())
+    return r
+}
+
+func (r *sqliteUserRepository) Create(ctx context.Context, name string, dob time.Time) (*db.User, error) {
+    row, err := r.q(ctx).CreateUser(ctx, sqlitedb.CreateUserParams{
+        Name: name,
+        Dob:  truncateDate(dob),
+    })
+    if err != nil {
+        return nil, err
+    }
+    
+    user := fromSQLite(row)
+    r.publish(ctx, events.UserCreated, user.ID, &user)
+    return &user, nil
+}
+
+func (r *sqliteUserRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
+    row, err := r.q(ctx).GetUserByID(ctx, int64(id))
+    if err != nil {
+        return nil, err
+    }
+    
+    user := fromSQLite(row)
+    return &user, nil
+}
+
+func (r *sqliteUserRepository) List(ctx context.Context, limit, offset int32) ([]*db.User, error) {
+    rows, err := r.q(ctx).ListUsers(ctx, sqlitedb.ListUsersParams{
+        Limit:  int64(limit),
+        Offset: int64(offset),
+    })
+    if err != nil {
+        return nil, err
+    }
+    return fromSQLiteRows(rows), nil
+}
+
+func (r *sqliteUserRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error) {
+    row, err := r.q(ctx).UpdateUser(ctx, sqlitedb.UpdateUserParams{
+        ID:   int64(id),
+        Name: name,
+        Dob:  truncateDate(dob),
+    })
+    if err != nil {
+        return nil, err
+    }
+    
+    user := fromSQLite(row)
+    r.publish(ctx, events.UserUpdated, user.ID, &user)
+    return &user, nil
+}
+
+func (r *sqliteUserRepository) Delete(ctx context.Context, id int32) error {
+    if err := r.q(ctx).DeleteUser(ctx, int64(id)); err != nil {
+        return err
+    }
+    
+    r.publish(ctx, events.UserDeleted, id, nil)
+    return nil
+}
+
+func (r *sqliteUserRepository) Count(ctx context.Context) (int64, error) {
+    return r.q(ctx).CountUsers(ctx)
+}
+
+func (r *sqliteUserRepository) GetByIDs(ctx context.Context, ids []int32) ([]*db.User, error) {
+    wide := make([]int64, len(ids))
+    for i, id := range ids {
+        wide[i] = int64(id)
+    }
+    
+    rows, err := r.q(ctx).GetUsersByIDs(ctx, wide)
+    if err != nil {
+        return nil, err
+    }
+    return fromSQLiteRows(rows), nil
+}
+
+func (r *sqliteUserRepository) Search(ctx context.Context, filter UserFilter, afterID, limit int32) ([]*db.User, error) {
+    params := sqlitedb.SearchUsersParams{
+        AfterID:  int64(afterID),
+        RowLimit: int64(limit),
+    }
+    if filter.NameContains != "" {
+        params.NameContains = sql.NullString{String: filter.NameContains, Valid: true}
+    }
+    if filter.BornAfter != nil {
+        params.BornAfter = sql.NullTime{Time: truncateDate(*filter.BornAfter), Valid: true}
+    }
+    if filter.BornBefore != nil {
+        params.BornBefore = sql.NullTime{Time: truncateDate(*filter.BornBefore), Valid: true}
+    }
+    
+    rows, err := r.q(ctx).SearchUsers(ctx, params)
+    if err != nil {
+        return nil, err
+    }
+    return fromSQLiteRows(rows), nil
+}
+
+// q returns queries bound to the transaction in ctx, if there is one.
+func (r *sqliteUserRepository) q(ctx context.Context) *sqlitedb.Queries {
+    if tx, ok := database.TxFromContext(ctx); ok {
+        return r.queries.WithTx(tx)
+    }
+    return r.queries
+}
+
+// publish is best effort: the write has already committed, and the broker
+// drops rather than blocks when subscribers fall behind.
+func (r *sqliteUserRepository) publish(ctx context.Context, eventType events.Type, id int32, user *db.User) {
+    if r.publisher == nil {
+        return
+    }
+    
+    event := events.Event{
+        ID:         r.eventID.Add(1),
+        Type:       eventType,
+        UserID:     id,
+        OccurredAt: time.Now().UTC(),
+    }
+    if user != nil {
+        event.User = &models.UserResponse{
+            ID:   user.ID,
+            Name: user.Name,
+            DOB:  user.Dob.Format("2006-01-02"),
+        }
+    }
+    database.AfterCommit(ctx, func() {
+        if err := r.publisher.Publish(context.WithoutCancel(ctx), event); err != nil {
+            logger.FromContext(ctx).Warn("Failed to publish user event",
+                zap.String("type", string(event.Type)),
+                zap.Int32("user_id", id),
+                zap.Error(err),
+            )
+        }
+    })
+}
+
+func fromSQLite(row sqlitedb.User) db.User {
+    return db.User{
+        ID:   int32(row.ID),
+        Name: row.Name,
+        Dob:  truncateDate(row.Dob),
+    }
+}
+
+func fromSQLiteRows(rows []sqlitedb.User) []*db.User {
+    result := make([]*db.User, len(rows))
+    for i := range rows {
+        user := fromSQLite(rows[i])
+        result[i] = &user
+    }
+    return result
+}
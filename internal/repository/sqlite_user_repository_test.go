@@ -0,0 +1,24 @@
+package repository_test
+
+import (
+    "path/filepath"
+    "testing"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+    "github.com/adityaK87/go-backend-assignment/internal/repository/repotest"
+)
+
+func TestSQLiteUserRepository(t *testing.T) {
+    repotest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+        db, _, err := database.Open("sqlite://"+filepath.Join(t.TempDir(), "users.db"), 0)
+        if err != nil {
+            t.Fatalf("open sqlite: %v", err)
+        }
+        t.Cleanup(func() { db.Close() })
+        return repository.NewSQLiteUserRepository(db, events.NewBroker(zap.NewNop()))
+    })
+}
@@ -3,11 +3,23 @@ package repository
 import (
     "context"
     "database/sql"
+    "encoding/json"
     "time"
     
     "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/db/sqlite/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/redact"
 )
 
+// sqlc output cannot carry pii tags
+func init() {
+    redact.Register(db.User{}, "Name", "Dob")
+    redact.Register(sqlitedb.User{}, "Name", "Dob")
+}
+
 type UserRepository interface {
     Create(ctx context.Context, name string, dob time.Time) (*db.User, error)
     GetByID(ctx context.Context, id int32) (*db.User, error)
@@ -15,22 +27,43 @@ type UserRepository interface {
     Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error)
     Delete(ctx context.Context, id int32) error
     Count(ctx context.Context) (int64, error)
+    GetByIDs(ctx context.Context, ids []int32) ([]*db.User, error)
+    Search(ctx context.Context, filter UserFilter, afterID, limit int32) ([]*db.User, error)
+}
+
+type UserFilter struct {
+    NameContains string
+    BornAfter    *time.Time
+    BornBefore   *time.Time
 }
 
+// userRepository writes an outbox row in the same transaction as every user
+// mutation, so events are recorded if and only if the change commits. Calls
+// made inside database.TxManager.WithinTx join that transaction.
 type userRepository struct {
+    db      *sql.DB
     queries *db.Queries
 }
 
 func NewUserRepository(database *sql.DB) UserRepository {
     return &userRepository{
+        db:      database,
         queries: db.New(database),
     }
 }
 
 func (r *userRepository) Create(ctx context.Context, name string, dob time.Time) (*db.User, error) {
-    user, err := r.queries.CreateUser(ctx, db.CreateUserParams{
-        Name: name,
-        Dob:  dob,
+    var user db.User
+    err := r.withTx(ctx, func(q *db.Queries) error {
+        var err error
+        user, err = q.CreateUser(ctx, db.CreateUserParams{
+            Name: name,
+            Dob:  dob,
+        })
+        if err != nil {
+            return err
+        }
+        return insertEvent(ctx, q, events.UserCreated, user.ID, &user)
     })
     if err != nil {
         return nil, err
@@ -39,7 +72,7 @@ func (r *userRepository) Create(ctx context.Context, name string, dob time.Time)
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id int32) (*db.User, error) {
-    user, err := r.queries.GetUserByID(ctx, id)
+    user, err := r.q(ctx).GetUserByID(ctx, id)
     if err != nil {
         return nil, err
     }
@@ -47,7 +80,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int32) (*db.User, error
 }
 
 func (r *userRepository) List(ctx context.Context, limit, offset int32) ([]*db.User, error) {
-    users, err := r.queries.ListUsers(ctx, db.ListUsersParams{
+    users, err := r.q(ctx).ListUsers(ctx, db.ListUsersParams{
         Limit:  limit,
         Offset: offset,
     })
@@ -63,10 +96,18 @@ func (r *userRepository) List(ctx context.Context, limit, offset int32) ([]*db.U
 }
 
 func (r *userRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (*db.User, error) {
-    user, err := r.queries.UpdateUser(ctx, db.UpdateUserParams{
-        ID:   id,
-        Name: name,
-        Dob:  dob,
+    var user db.User
+    err := r.withTx(ctx, func(q *db.Queries) error {
+        var err error
+        user, err = q.UpdateUser(ctx, db.UpdateUserParams{
+            ID:   id,
+            Name: name,
+            Dob:  dob,
+        })
+        if err != nil {
+            return err
+        }
+        return insertEvent(ctx, q, events.UserUpdated, user.ID, &user)
     })
     if err != nil {
         return nil, err
@@ -75,9 +116,106 @@ func (r *userRepository) Update(ctx context.Context, id int32, name string, dob
 }
 
 func (r *userRepository) Delete(ctx context.Context, id int32) error {
-    return r.queries.DeleteUser(ctx, id)
+    return r.withTx(ctx, func(q *db.Queries) error {
+        if err := q.DeleteUser(ctx, id); err != nil {
+            return err
+        }
+        return insertEvent(ctx, q, events.UserDeleted, id, nil)
+    })
 }
 
 func (r *userRepository) Count(ctx context.Context) (int64, error) {
-    return r.queries.CountUsers(ctx)
+    return r.q(ctx).CountUsers(ctx)
+}
+
+func (r *userRepository) GetByIDs(ctx context.Context, ids []int32) ([]*db.User, error) {
+    users, err := r.q(ctx).GetUsersByIDs(ctx, ids)
+    if err != nil {
+        return nil, err
+    }
+    
+    result := make([]*db.User, len(users))
+    for i := range users {
+        result[i] = &users[i]
+    }
+    return result, nil
+}
+
+func (r *userRepository) Search(ctx context.Context, filter UserFilter, afterID, limit int32) ([]*db.User, error) {
+    params := db.SearchUsersParams{
+        AfterID:  afterID,
+        RowLimit: limit,
+    }
+    if filter.NameContains != "" {
+        params.NameContains = sql.NullString{String: filter.NameContains, Valid: true}
+    }
+    if filter.BornAfter != nil {
+        params.BornAfter = sql.NullTime{Time: *filter.BornAfter, Valid: true}
+    }
+    if filter.BornBefore != nil {
+        params.BornBefore = sql.NullTime{Time: *filter.BornBefore, Valid: true}
+    }
+    
+    users, err := r.q(ctx).SearchUsers(ctx, params)
+    if err != nil {
+        return nil, err
+    }
+    
+    result := make([]*db.User, len(users))
+    for i := range users {
+        result[i] = &users[i]
+    }
+    return result, nil
+}
+
+// q returns queries bound to the transaction in ctx, if there is one.
+func (r *userRepository) q(ctx context.Context) *db.Queries {
+    if tx, ok := database.TxFromContext(ctx); ok {
+        return r.queries.WithTx(tx)
+    }
+    return r.queries
+}
+
+func (r *userRepository) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+    if tx, ok := database.TxFromContext(ctx); ok {
+        return fn(r.queries.WithTx(tx))
+    }
+    
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    
+    if err := fn(r.queries.WithTx(tx)); err != nil {
+        _ = tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
+
+func insertEvent(ctx context.Context, q *db.Queries, eventType events.Type, id int32, user *db.User) error {
+    event := events.Event{
+        Type:       eventType,
+        UserID:     id,
+        OccurredAt: time.Now().UTC(),
+    }
+    if user != nil {
+        event.User = &models.UserResponse{
+            ID:   user.ID,
+            Name: user.Name,
+            DOB:  user.Dob.Format("2006-01-02"),
+        }
+    }
+    
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    
+    _, err = q.InsertOutboxEvent(ctx, db.InsertOutboxEventParams{
+        AggregateID: id,
+        EventType:   string(eventType),
+        Payload:     payload,
+    })
+    return err
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package repository_test
+
+import (
+    "os"
+    "testing"
+    
+    "github.com/adityaK87/go-backend-assignment/db/migrations"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+    "github.com/adityaK87/go-backend-assignment/internal/repository/repotest"
+)
+
+// TestUserRepository runs the contract against the Postgres repository when
+// TEST_DATABASE_URL points at a disposable database; its users and outbox
+// tables are emptied before every subtest.
+func TestUserRepository(t *testing.T) {
+    dsn := os.Getenv("TEST_DATABASE_URL")
+    if dsn == "" {
+        t.Skip("TEST_DATABASE_URL is not set")
+    }
+    
+    db, _, err := database.Open(dsn, 0)
+    if err != nil {
+        t.Fatalf("open postgres: %v", err)
+    }
+    t.Cleanup(func() { db.Close() })
+    if err := database.Migrate(db, migrations.FS); err != nil {
+        t.Fatalf("migrate postgres: %v", err)
+    }
+    
+    repotest.RunUserRepositoryContract(t, func(t *testing.T) repository.UserRepository {
+        if _, err := db.Exec(`TRUNCATE users, outbox RESTART IDENTITY`); err != nil {
+            t.Fatalf("reset tables: %v", err)
+        }
+        return repository.NewUserRepository(db)
+    })
+}
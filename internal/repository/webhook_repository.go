@@ -0,0 +1,176 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "time"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+)
+
+type WebhookRepository interface {
+    CreateSubscription(ctx context.Context, url string, eventTypes []string, secret string) (*db.WebhookSubscription, error)
+    GetSubscription(ctx context.Context, id int32) (*db.WebhookSubscription, error)
+    ListSubscriptions(ctx context.Context) ([]*db.WebhookSubscription, error)
+    ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*db.WebhookSubscription, error)
+    DeleteSubscription(ctx context.Context, id int32) error
+    // CreateDelivery returns nil when the event already has a delivery for
+    // the subscription. An eventID of 0 is not checked.
+    CreateDelivery(ctx context.Context, subscriptionID int32, eventID int64, eventType string, payload []byte) (*db.WebhookDelivery, error)
+    GetDelivery(ctx context.Context, id int64) (*db.WebhookDelivery, error)
+    ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int32) ([]*db.WebhookDelivery, error)
+    ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int32) ([]*db.WebhookDelivery, error)
+    MarkDeliverySucceeded(ctx context.Context, id int64, statusCode int) error
+    MarkDeliveryFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, statusCode int, lastError string) error
+    Redeliver(ctx context.Context, id int64) (*db.WebhookDelivery, error)
+}
+
+type DeliveryFilter struct {
+    Status         string
+    SubscriptionID int32
+}
+
+type webhookRepository struct {
+    queries *db.Queries
+}
+
+func NewWebhookRepository(database *sql.DB) WebhookRepository {
+    return &webhookRepository{
+        queries: db.New(database),
+    }
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, url string, eventTypes []string, secret string) (*db.WebhookSubscription, error) {
+    subscription, err := r.queries.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+        Url:        url,
+        EventTypes: eventTypes,
+        Secret:     secret,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &subscription, nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id int32) (*db.WebhookSubscription, error) {
+    subscription, err := r.queries.GetWebhookSubscription(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    return &subscription, nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]*db.WebhookSubscription, error) {
+    subscriptions, err := r.queries.ListWebhookSubscriptions(ctx)
+    if err != nil {
+        return nil, err
+    }
+    return subscriptionPointers(subscriptions), nil
+}
+
+func (r *webhookRepository) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*db.WebhookSubscription, error) {
+    subscriptions, err := r.queries.ListWebhookSubscriptionsForEvent(ctx, eventType)
+    if err != nil {
+        return nil, err
+    }
+    return subscriptionPointers(subscriptions), nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id int32) error {
+    return r.queries.DeleteWebhookSubscription(ctx, id)
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, subscriptionID int32, eventID int64, eventType string, payload []byte) (*db.WebhookDelivery, error) {
+    delivery, err := r.queries.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+        SubscriptionID: subscriptionID,
+        EventID:        sql.NullInt64{Int64: eventID, Valid: eventID != 0},
+        EventType:      eventType,
+        Payload:        payload,
+    })
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &delivery, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id int64) (*db.WebhookDelivery, error) {
+    delivery, err := r.queries.GetWebhookDelivery(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int32) ([]*db.WebhookDelivery, error) {
+    params := db.ListWebhookDeliveriesParams{
+        RowLimit:  limit,
+        RowOffset: offset,
+    }
+    if filter.Status != "" {
+        params.Status = sql.NullString{String: filter.Status, Valid: true}
+    }
+    if filter.SubscriptionID != 0 {
+        params.SubscriptionID = sql.NullInt32{Int32: filter.SubscriptionID, Valid: true}
+    }
+    
+    deliveries, err := r.queries.ListWebhookDeliveries(ctx, params)
+    if err != nil {
+        return nil, err
+    }
+    return deliveryPointers(deliveries), nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int32) ([]*db.WebhookDelivery, error) {
+    deliveries, err := r.queries.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+        LeaseSeconds: int32(lease / time.Second),
+        RowLimit:     limit,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return deliveryPointers(deliveries), nil
+}
+
+func (r *webhookRepository) MarkDeliverySucceeded(ctx context.Context, id int64, statusCode int) error {
+    return r.queries.MarkWebhookDeliverySucceeded(ctx, db.MarkWebhookDeliverySucceededParams{
+        ID:             id,
+        LastStatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: true},
+    })
+}
+
+func (r *webhookRepository) MarkDeliveryFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, statusCode int, lastError string) error {
+    return r.queries.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+        ID:             id,
+        Status:         status,
+        NextAttemptAt:  nextAttemptAt,
+        LastStatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+        LastError:      sql.NullString{String: lastError, Valid: lastError != ""},
+    })
+}
+
+func (r *webhookRepository) Redeliver(ctx context.Context, id int64) (*db.WebhookDelivery, error) {
+    delivery, err := r.queries.RedeliverWebhookDelivery(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    return &delivery, nil
+}
+
+func subscriptionPointers(subscriptions []db.WebhookSubscription) []*db.WebhookSubscription {
+    result := make([]*db.WebhookSubscription, len(subscriptions))
+    for i := range subscriptions {
+        result[i] = &subscriptions[i]
+    }
+    return result
+}
+
+func deliveryPointers(deliveries []db.WebhookDelivery) []*db.WebhookDelivery {
+    result := make([]*db.WebhookDelivery, len(deliveries))
+    for i := range deliveries {
+        result[i] = &deliveries[i]
+    }
+    return result
+}
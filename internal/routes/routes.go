@@ -1,25 +1,96 @@
 package routes
 
 import (
+    "net/http"
+    "time"
+    
     "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/middleware/adaptor"
+    "github.com/gofiber/fiber/v2/middleware/expvar"
+    "github.com/gofiber/fiber/v2/middleware/pprof"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "github.com/adityaK87/go-backend-assignment/internal/auth"
     "github.com/adityaK87/go-backend-assignment/internal/handler"
+    "github.com/adityaK87/go-backend-assignment/internal/middleware"
 )
 
-func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler) {
+func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, userEventsHandler *handler.UserEventsHandler, graphqlHandler http.Handler, healthHandler *handler.HealthHandler, versionHandler *handler.VersionHandler, requestTimeout time.Duration) {
     api := app.Group("/")
+    route := middleware.Route()
+    timeout := middleware.Timeout(requestTimeout)
+    noStore := middleware.NoStore()
+    
+    // User routes carry personal data and must not be cached
+    users := api.Group("/users", noStore)
+    users.Post("/", route, timeout, userHandler.CreateUser)
+    users.Get("/", route, timeout, userHandler.ListUsers)
+    users.Get("/events", route, userEventsHandler.Stream)
+    users.Get("/:id", route, timeout, userHandler.GetUser)
+    users.Put("/:id", route, timeout, userHandler.UpdateUser)
+    users.Delete("/:id", route, timeout, userHandler.DeleteUser)
+    
+    // GraphQL
+    app.All("/graphql", route, noStore, timeout, withUserContext(graphqlHandler))
+    
+    // Probes; /health is kept for existing load balancer configs and
+    // reports readiness
+    app.Get("/livez", healthHandler.Livez)
+    app.Get("/readyz", healthHandler.Readyz)
+    app.Get("/startupz", healthHandler.Startupz)
+    app.Get("/health", healthHandler.Readyz)
+    
+    // Build version
+    app.Get("/version", versionHandler.Get)
+}
+
+// SetupAdminRoutes registers the operational endpoints, which are served on
+// the admin listener only.
+func SetupAdminRoutes(app *fiber.App, healthHandler *handler.HealthHandler, logLevelHandler *handler.LogLevelHandler, webhookHandler *handler.WebhookHandler, requestTimeout time.Duration) {
+    route := middleware.Route()
+    timeout := middleware.Timeout(requestTimeout)
+    admin := middleware.RequireScope(auth.ScopeAdmin)
     
-    // User routes
-    users := api.Group("/users")
-    users.Post("/", userHandler.CreateUser)
-    users.Get("/", userHandler.ListUsers)
-    users.Get("/:id", userHandler.GetUser)
-    users.Put("/:id", userHandler.UpdateUser)
-    users.Delete("/:id", userHandler.DeleteUser)
-    
-    // Health check
-    app.Get("/health", func(c *fiber.Ctx) error {
-        return c.JSON(fiber.Map{
-            "status": "ok",
-        })
-    })
+    // Prometheus metrics
+    app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+    
+    // Probes whose ?verbose output includes check errors
+    app.Get("/livez", healthHandler.Livez)
+    app.Get("/readyz", healthHandler.Readyz)
+    app.Get("/startupz", healthHandler.Startupz)
+    
+    // Runtime log level, for holders of the admin scope
+    app.Get("/admin/log-level", route, admin, logLevelHandler.GetLevel)
+    app.Put("/admin/log-level", route, admin, logLevelHandler.SetLevel)
+    
+    // Webhook subscriptions choose where user data is sent and deliveries
+    // hold it, so both need the admin scope. Absent on backends without
+    // webhook storage.
+    if webhookHandler != nil {
+        webhooks := app.Group("/admin/webhooks")
+        webhooks.Post("/subscriptions", route, admin, timeout, webhookHandler.CreateSubscription)
+        webhooks.Get("/subscriptions", route, admin, timeout, webhookHandler.ListSubscriptions)
+        webhooks.Delete("/subscriptions/:id", route, admin, timeout, webhookHandler.DeleteSubscription)
+        webhooks.Get("/deliveries", route, admin, timeout, webhookHandler.ListDeliveries)
+        webhooks.Post("/deliveries/:id/redeliver", route, admin, timeout, webhookHandler.Redeliver)
+    }
+}
+
+// SetupDiagnosticsRoutes registers pprof under /debug/pprof, expvar runtime
+// stats at /debug/vars and a goroutine dump at /debug/goroutines, all behind
+// guards. CPU profiles and traces run for ?seconds=, up to maxProfile.
+func SetupDiagnosticsRoutes(app *fiber.App, diagnosticsHandler *handler.DiagnosticsHandler, maxProfile time.Duration, guards ...fiber.Handler) {
+    debug := app.Group("/debug", guards...)
+    debug.Get("/goroutines", diagnosticsHandler.Goroutines)
+    debug.Use(middleware.MaxProfileDuration(maxProfile), pprof.New(), expvar.New())
+}
+
+// withUserContext serves h with the request context from
+// middleware.RequestContext, which adaptor.HTTPHandler alone would drop.
+func withUserContext(h http.Handler) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        ctx := c.UserContext()
+        return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            h.ServeHTTP(w, r.WithContext(ctx))
+        })(c)
+    }
 }
\ No newline at end of file
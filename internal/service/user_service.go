@@ -6,52 +6,71 @@ import (
     "errors"
     "time"
     
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/database"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
     "github.com/adityaK87/go-backend-assignment/internal/models"
     "github.com/adityaK87/go-backend-assignment/internal/repository"
     "go.uber.org/zap"
 )
 
+var (
+    ErrUserNotFound = errors.New("user not found")
+    ErrInvalidDate  = errors.New("invalid date format")
+    ErrFutureDOB    = errors.New("date of birth cannot be in the future")
+)
+
 type UserService interface {
     CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, error)
     GetUserByID(ctx context.Context, id int32) (*models.UserResponse, error)
     ListUsers(ctx context.Context, page, limit int) ([]*models.UserResponse, error)
     UpdateUser(ctx context.Context, id int32, req models.UpdateUserRequest) (*models.UserResponse, error)
     DeleteUser(ctx context.Context, id int32) error
+    GetUsersByIDs(ctx context.Context, ids []int32) ([]*models.UserResponse, error)
+    SearchUsers(ctx context.Context, filter models.UserFilter, afterID int32, limit int) ([]*models.UserResponse, bool, error)
 }
 
 type userService struct {
     repo   repository.UserRepository
+    tx     database.TxManager
     logger *zap.Logger
 }
 
-func NewUserService(repo repository.UserRepository, logger *zap.Logger) UserService {
+func NewUserService(repo repository.UserRepository, tx database.TxManager, logger *zap.Logger) UserService {
     return &userService{
         repo:   repo,
+        tx:     tx,
         logger: logger,
     }
 }
 
+// log returns the request logger from ctx, so lines carry the caller's
+// request and trace IDs, falling back to the service logger.
+func (s *userService) log(ctx context.Context) *zap.Logger {
+    return logger.FromContextOr(ctx, s.logger)
+}
+
 func (s *userService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, error) {
     // Parse DOB
     dob, err := time.Parse("2006-01-02", req.DOB)
     if err != nil {
-        s.logger.Error("Failed to parse DOB", zap.Error(err))
-        return nil, errors.New("invalid date format")
+        s.log(ctx).Error("Failed to parse DOB", zap.Error(err))
+        return nil, ErrInvalidDate
     }
     
     // Validate DOB is not in the future
     if dob.After(time.Now()) {
-        return nil, errors.New("date of birth cannot be in the future")
+        return nil, ErrFutureDOB
     }
     
     // Create user
     user, err := s.repo.Create(ctx, req.Name, dob)
     if err != nil {
-        s.logger.Error("Failed to create user", zap.Error(err))
+        s.log(ctx).Error("Failed to create user", zap.Error(err))
         return nil, err
     }
     
-    s.logger.Info("User created successfully", zap.Int32("user_id", user.ID))
+    s.log(ctx).Info("User created successfully", zap.Int32("user_id", user.ID))
     
     return &models.UserResponse{
         ID:   user.ID,
@@ -64,9 +83,9 @@ func (s *userService) GetUserByID(ctx context.Context, id int32) (*models.UserRe
     user, err := s.repo.GetByID(ctx, id)
     if err != nil {
         if err == sql.ErrNoRows {
-            return nil, errors.New("user not found")
+            return nil, ErrUserNotFound
         }
-        s.logger.Error("Failed to get user", zap.Error(err), zap.Int32("user_id", id))
+        s.log(ctx).Error("Failed to get user", zap.Error(err), zap.Int32("user_id", id))
         return nil, err
     }
     
@@ -92,52 +111,47 @@ func (s *userService) ListUsers(ctx context.Context, page, limit int) ([]*models
     
     users, err := s.repo.List(ctx, int32(limit), int32(offset))
     if err != nil {
-        s.logger.Error("Failed to list users", zap.Error(err))
+        s.log(ctx).Error("Failed to list users", zap.Error(err))
         return nil, err
     }
     
-    response := make([]*models.UserResponse, len(users))
-    for i, user := range users {
-        age := models.CalculateAge(user.Dob)
-        response[i] = &models.UserResponse{
-            ID:   user.ID,
-            Name: user.Name,
-            DOB:  user.Dob.Format("2006-01-02"),
-            Age:  &age,
-        }
-    }
-    
-    return response, nil
+    return toUserResponses(users), nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, id int32, req models.UpdateUserRequest) (*models.UserResponse, error) {
-    // Check if user exists
-    _, err := s.repo.GetByID(ctx, id)
-    if err != nil {
-        if err == sql.ErrNoRows {
-            return nil, errors.New("user not found")
+    var user *db.User
+    err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+        // Check if user exists
+        if _, err := s.repo.GetByID(ctx, id); err != nil {
+            return err
         }
-        return nil, err
-    }
-    
-    // Parse DOB
-    dob, err := time.Parse("2006-01-02", req.DOB)
-    if err != nil {
-        return nil, errors.New("invalid date format")
-    }
-    
-    if dob.After(time.Now()) {
-        return nil, errors.New("date of birth cannot be in the future")
-    }
-    
-    // Update user
-    user, err := s.repo.Update(ctx, id, req.Name, dob)
+        
+        // Parse DOB
+        dob, err := time.Parse("2006-01-02", req.DOB)
+        if err != nil {
+            return ErrInvalidDate
+        }
+        
+        if dob.After(time.Now()) {
+            return ErrFutureDOB
+        }
+        
+        // Update user
+        user, err = s.repo.Update(ctx, id, req.Name, dob)
+        return err
+    })
     if err != nil {
-        s.logger.Error("Failed to update user", zap.Error(err), zap.Int32("user_id", id))
+        switch {
+        case errors.Is(err, sql.ErrNoRows):
+            return nil, ErrUserNotFound
+        case errors.Is(err, ErrInvalidDate), errors.Is(err, ErrFutureDOB):
+            return nil, err
+        }
+        s.log(ctx).Error("Failed to update user", zap.Error(err), zap.Int32("user_id", id))
         return nil, err
     }
     
-    s.logger.Info("User updated successfully", zap.Int32("user_id", user.ID))
+    s.log(ctx).Info("User updated successfully", zap.Int32("user_id", user.ID))
     
     return &models.UserResponse{
         ID:   user.ID,
@@ -147,20 +161,84 @@ func (s *userService) UpdateUser(ctx context.Context, id int32, req models.Updat
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id int32) error {
-    // Check if user exists
-    _, err := s.repo.GetByID(ctx, id)
+    err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+        // Check if user exists
+        if _, err := s.repo.GetByID(ctx, id); err != nil {
+            return err
+        }
+        return s.repo.Delete(ctx, id)
+    })
     if err != nil {
-        if err == sql.ErrNoRows {
-            return errors.New("user not found")
+        if errors.Is(err, sql.ErrNoRows) {
+            return ErrUserNotFound
         }
+        s.log(ctx).Error("Failed to delete user", zap.Error(err), zap.Int32("user_id", id))
         return err
     }
     
-    if err := s.repo.Delete(ctx, id); err != nil {
-        s.logger.Error("Failed to delete user", zap.Error(err), zap.Int32("user_id", id))
-        return err
+    s.log(ctx).Info("User deleted successfully", zap.Int32("user_id", id))
+    return nil
+}
+
+func (s *userService) GetUsersByIDs(ctx context.Context, ids []int32) ([]*models.UserResponse, error) {
+    users, err := s.repo.GetByIDs(ctx, ids)
+    if err != nil {
+        s.log(ctx).Error("Failed to get users", zap.Error(err), zap.Int("count", len(ids)))
+        return nil, err
     }
     
-    s.logger.Info("User deleted successfully", zap.Int32("user_id", id))
-    return nil
+    return toUserResponses(users), nil
+}
+
+// SearchUsers returns up to limit users with IDs greater than afterID, and
+// whether more matching users exist beyond them.
+func (s *userService) SearchUsers(ctx context.Context, filter models.UserFilter, afterID int32, limit int) ([]*models.UserResponse, bool, error) {
+    if limit < 1 || limit > 100 {
+        limit = 10
+    }
+    
+    var repoFilter repository.UserFilter
+    repoFilter.NameContains = filter.NameContains
+    if filter.BornAfter != "" {
+        bornAfter, err := time.Parse("2006-01-02", filter.BornAfter)
+        if err != nil {
+            return nil, false, ErrInvalidDate
+        }
+        repoFilter.BornAfter = &bornAfter
+    }
+    if filter.BornBefore != "" {
+        bornBefore, err := time.Parse("2006-01-02", filter.BornBefore)
+        if err != nil {
+            return nil, false, ErrInvalidDate
+        }
+        repoFilter.BornBefore = &bornBefore
+    }
+    
+    // Fetch one extra row to find out whether there is a next page
+    users, err := s.repo.Search(ctx, repoFilter, afterID, int32(limit+1))
+    if err != nil {
+        s.log(ctx).Error("Failed to search users", zap.Error(err))
+        return nil, false, err
+    }
+    
+    hasMore := len(users) > limit
+    if hasMore {
+        users = users[:limit]
+    }
+    
+    return toUserResponses(users), hasMore, nil
+}
+
+func toUserResponses(users []*db.User) []*models.UserResponse {
+    response := make([]*models.UserResponse, len(users))
+    for i, user := range users {
+        age := models.CalculateAge(user.Dob)
+        response[i] = &models.UserResponse{
+            ID:   user.ID,
+            Name: user.Name,
+            DOB:  user.Dob.Format("2006-01-02"),
+            Age:  &age,
+        }
+    }
+    return response
 }
\ No newline at end of file
@@ -0,0 +1,179 @@
+package service
+
+import (
+    "context"
+    "crypto/rand"
+    "database/sql"
+    "encoding/hex"
+    "errors"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/logger"
+    "github.com/adityaK87/go-backend-assignment/internal/models"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+    "go.uber.org/zap"
+)
+
+var (
+    ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+    ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+type WebhookService interface {
+    CreateSubscription(ctx context.Context, req models.CreateWebhookSubscriptionRequest) (*models.WebhookSubscriptionResponse, error)
+    ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscriptionResponse, error)
+    DeleteSubscription(ctx context.Context, id int32) error
+    ListDeliveries(ctx context.Context, query models.WebhookDeliveryQuery) ([]*models.WebhookDeliveryResponse, error)
+    Redeliver(ctx context.Context, id int64) (*models.WebhookDeliveryResponse, error)
+}
+
+type webhookService struct {
+    repo   repository.WebhookRepository
+    logger *zap.Logger
+}
+
+func NewWebhookService(repo repository.WebhookRepository, logger *zap.Logger) WebhookService {
+    return &webhookService{
+        repo:   repo,
+        logger: logger,
+    }
+}
+
+// log prefers the request logger in ctx over the service logger.
+func (s *webhookService) log(ctx context.Context) *zap.Logger {
+    return logger.FromContextOr(ctx, s.logger)
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, req models.CreateWebhookSubscriptionRequest) (*models.WebhookSubscriptionResponse, error) {
+    // Generate a signing secret unless the caller supplied one
+    secret := req.Secret
+    if secret == "" {
+        buf := make([]byte, 32)
+        if _, err := rand.Read(buf); err != nil {
+            return nil, err
+        }
+        secret = "whsec_" + hex.EncodeToString(buf)
+    }
+    
+    subscription, err := s.repo.CreateSubscription(ctx, req.URL, req.EventTypes, secret)
+    if err != nil {
+        s.log(ctx).Error("Failed to create webhook subscription", zap.Error(err))
+        return nil, err
+    }
+    
+    s.log(ctx).Info("Webhook subscription created", zap.Int32("subscription_id", subscription.ID))
+    
+    // The secret is only ever returned on creation
+    response := toSubscriptionResponse(subscription)
+    response.Secret = subscription.Secret
+    return response, nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscriptionResponse, error) {
+    subscriptions, err := s.repo.ListSubscriptions(ctx)
+    if err != nil {
+        s.log(ctx).Error("Failed to list webhook subscriptions", zap.Error(err))
+        return nil, err
+    }
+    
+    response := make([]*models.WebhookSubscriptionResponse, len(subscriptions))
+    for i, subscription := range subscriptions {
+        response[i] = toSubscriptionResponse(subscription)
+    }
+    return response, nil
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id int32) error {
+    // Check if subscription exists
+    _, err := s.repo.GetSubscription(ctx, id)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return ErrSubscriptionNotFound
+        }
+        return err
+    }
+    
+    if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+        s.log(ctx).Error("Failed to delete webhook subscription", zap.Error(err), zap.Int32("subscription_id", id))
+        return err
+    }
+    
+    s.log(ctx).Info("Webhook subscription deleted", zap.Int32("subscription_id", id))
+    return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, query models.WebhookDeliveryQuery) ([]*models.WebhookDeliveryResponse, error) {
+    page, limit := query.Page, query.Limit
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 || limit > 100 {
+        limit = 20
+    }
+    
+    filter := repository.DeliveryFilter{
+        Status:         query.Status,
+        SubscriptionID: query.SubscriptionID,
+    }
+    deliveries, err := s.repo.ListDeliveries(ctx, filter, int32(limit), int32((page-1)*limit))
+    if err != nil {
+        s.log(ctx).Error("Failed to list webhook deliveries", zap.Error(err))
+        return nil, err
+    }
+    
+    response := make([]*models.WebhookDeliveryResponse, len(deliveries))
+    for i, delivery := range deliveries {
+        response[i] = toDeliveryResponse(delivery)
+    }
+    return response, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, id int64) (*models.WebhookDeliveryResponse, error) {
+    delivery, err := s.repo.Redeliver(ctx, id)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, ErrDeliveryNotFound
+        }
+        s.log(ctx).Error("Failed to redeliver webhook", zap.Error(err), zap.Int64("delivery_id", id))
+        return nil, err
+    }
+    
+    s.log(ctx).Info("Webhook delivery rescheduled", zap.Int64("delivery_id", id))
+    return toDeliveryResponse(delivery), nil
+}
+
+func toSubscriptionResponse(subscription *db.WebhookSubscription) *models.WebhookSubscriptionResponse {
+    return &models.WebhookSubscriptionResponse{
+        ID:         subscription.ID,
+        URL:        subscription.Url,
+        EventTypes: subscription.EventTypes,
+        Active:     subscription.Active,
+        CreatedAt:  subscription.CreatedAt,
+    }
+}
+
+func toDeliveryResponse(delivery *db.WebhookDelivery) *models.WebhookDeliveryResponse {
+    response := &models.WebhookDeliveryResponse{
+        ID:             delivery.ID,
+        SubscriptionID: delivery.SubscriptionID,
+        EventType:      delivery.EventType,
+        Payload:        delivery.Payload,
+        Status:         delivery.Status,
+        Attempts:       delivery.Attempts,
+        NextAttemptAt:  delivery.NextAttemptAt,
+        CreatedAt:      delivery.CreatedAt,
+    }
+    if delivery.EventID.Valid {
+        response.EventID = &delivery.EventID.Int64
+    }
+    if delivery.LastStatusCode.Valid {
+        response.LastStatusCode = &delivery.LastStatusCode.Int32
+    }
+    if delivery.LastError.Valid {
+        response.LastError = &delivery.LastError.String
+    }
+    if delivery.DeliveredAt.Valid {
+        response.DeliveredAt = &delivery.DeliveredAt.Time
+    }
+    return response
+}
@@ -0,0 +1,161 @@
+package sse
+
+import (
+    "context"
+    "sync"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+const subscriberBuffer = 64
+
+// Hub keeps a bounded replay buffer of recent events and fans new events
+// out to connected streams. A stream that falls behind is closed rather than
+// silently skipping events; the client then resumes with Last-Event-ID.
+type Hub struct {
+    mu          sync.Mutex
+    buffer      []events.Event
+    size        int
+    subscribers map[*subscriber]struct{}
+    closed      bool
+}
+
+type subscriber struct {
+    ch     chan events.Event
+    filter map[int32]struct{}
+}
+
+func NewHub(size int) *Hub {
+    return &Hub{
+        buffer:      make([]events.Event, 0, size),
+        size:        size,
+        subscribers: make(map[*subscriber]struct{}),
+    }
+}
+
+// Run feeds the hub from subscriber until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context, source events.Subscriber) {
+    ch, unsubscribe := source.Subscribe(256)
+    defer unsubscribe()
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case event, ok := <-ch:
+            if !ok {
+                return
+            }
+            _ = h.Publish(ctx, event)
+        }
+    }
+}
+
+func (h *Hub) Publish(ctx context.Context, event events.Event) error {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    
+    // A zero size turns replay off; live events are still fanned out
+    if h.size > 0 {
+        if len(h.buffer) == h.size {
+            copy(h.buffer, h.buffer[1:])
+            h.buffer = h.buffer[:h.size-1]
+        }
+        h.buffer = append(h.buffer, event)
+    }
+    
+    for sub := range h.subscribers {
+        if !sub.wants(event) {
+            continue
+        }
+        select {
+        case sub.ch <- event:
+        default:
+            delete(h.subscribers, sub)
+            close(sub.ch)
+        }
+    }
+    return nil
+}
+
+// Subscribe returns the buffered events after lastEventID (none when
+// lastEventID is zero, as for a first connection) together with a channel
+// of live events.
+// Both are taken under the same lock so nothing falls between them.
+func (h *Hub) Subscribe(lastEventID int64, userIDs []int32) ([]events.Event, <-chan events.Event, func()) {
+    sub := &subscriber{ch: make(chan events.Event, subscriberBuffer)}
+    if len(userIDs) > 0 {
+        sub.filter = make(map[int32]struct{}, len(userIDs))
+        for _, id := range userIDs {
+            sub.filter[id] = struct{}{}
+        }
+    }
+    
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    
+    if h.closed {
+        close(sub.ch)
+        return nil, sub.ch, func() {}
+    }
+    
+    var replay []events.Event
+    if lastEventID != 0 {
+        for _, event := range h.replayAfter(lastEventID) {
+            if sub.wants(event) {
+                replay = append(replay, event)
+            }
+        }
+    }
+    h.subscribers[sub] = struct{}{}
+    
+    unsubscribe := func() {
+        h.mu.Lock()
+        defer h.mu.Unlock()
+        if _, ok := h.subscribers[sub]; ok {
+            delete(h.subscribers, sub)
+            close(sub.ch)
+        }
+    }
+    return replay, sub.ch, unsubscribe
+}
+
+// replayAfter prefers the buffer position of lastEventID, since outbox IDs
+// can arrive slightly out of order across relays, and falls back to
+// comparing IDs when that event has already left the buffer. The result is
+// a copy, as Publish shifts the buffer in place.
+func (h *Hub) replayAfter(lastEventID int64) []events.Event {
+    for i, event := range h.buffer {
+        if event.ID == lastEventID {
+            return append([]events.Event(nil), h.buffer[i+1:]...)
+        }
+    }
+    
+    var replay []events.Event
+    for _, event := range h.buffer {
+        if event.ID > lastEventID {
+            replay = append(replay, event)
+        }
+    }
+    return replay
+}
+
+// Close ends every open stream so that the HTTP server can shut down.
+func (h *Hub) Close() {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    
+    h.closed = true
+    for sub := range h.subscribers {
+        delete(h.subscribers, sub)
+        close(sub.ch)
+    }
+}
+
+func (s *subscriber) wants(event events.Event) bool {
+    if s.filter == nil {
+        return true
+    }
+    _, ok := s.filter[event.UserID]
+    return ok
+}
@@ -0,0 +1,91 @@
+package sse
+
+import (
+    "context"
+    "slices"
+    "testing"
+    
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+)
+
+func TestHubReplay(t *testing.T) {
+    tests := []struct {
+        name        string
+        size        int
+        published   []int64
+        lastEventID int64
+        want        []int64
+    }{
+        {name: "zero size keeps nothing", size: 0, published: []int64{1, 2, 3}, lastEventID: 1, want: nil},
+        {name: "partly filled", size: 3, published: []int64{1, 2}, lastEventID: 1, want: []int64{2}},
+        {name: "exactly full", size: 3, published: []int64{1, 2, 3}, lastEventID: 1, want: []int64{2, 3}},
+        {name: "full drops the oldest", size: 2, published: []int64{1, 2, 3, 4}, lastEventID: 2, want: []int64{3, 4}},
+        {name: "evicted ID falls back to comparison", size: 2, published: []int64{1, 2, 3, 4}, lastEventID: 1, want: []int64{3, 4}},
+        {name: "no Last-Event-ID replays nothing", size: 2, published: []int64{1, 2}, lastEventID: 0, want: nil},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            hub := NewHub(tt.size)
+            for _, id := range tt.published {
+                if err := hub.Publish(context.Background(), events.Event{ID: id}); err != nil {
+                    t.Fatalf("Publish(%d): %v", id, err)
+                }
+            }
+            
+            replay, _, unsubscribe := hub.Subscribe(tt.lastEventID, nil)
+            defer unsubscribe()
+            if got := eventIDs(replay); !slices.Equal(got, tt.want) {
+                t.Errorf("replay = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestHubLiveEventsWithoutBuffer(t *testing.T) {
+    hub := NewHub(0)
+    _, live, unsubscribe := hub.Subscribe(0, []int32{7})
+    defer unsubscribe()
+    
+    for _, event := range []events.Event{{ID: 1, UserID: 3}, {ID: 2, UserID: 7}} {
+        if err := hub.Publish(context.Background(), event); err != nil {
+            t.Fatalf("Publish: %v", err)
+        }
+    }
+    
+    select {
+    case event := <-live:
+        if event.ID != 2 {
+            t.Errorf("got event %d, want 2 for the filtered user", event.ID)
+        }
+    default:
+        t.Fatal("no live event delivered")
+    }
+    select {
+    case event := <-live:
+        t.Errorf("unexpected event %d", event.ID)
+    default:
+    }
+}
+
+func TestHubReplayIsNotOverwritten(t *testing.T) {
+    hub := NewHub(2)
+    for _, id := range []int64{1, 2} {
+        _ = hub.Publish(context.Background(), events.Event{ID: id})
+    }
+    replay, _, unsubscribe := hub.Subscribe(1, nil)
+    defer unsubscribe()
+    
+    _ = hub.Publish(context.Background(), events.Event{ID: 3})
+    if got := eventIDs(replay); !slices.Equal(got, []int64{2}) {
+        t.Errorf("replay changed to %v after a later publish", got)
+    }
+}
+
+func eventIDs(list []events.Event) []int64 {
+    var ids []int64
+    for _, event := range list {
+        ids = append(ids, event.ID)
+    }
+    return ids
+}
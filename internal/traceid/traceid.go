@@ -0,0 +1,58 @@
+package traceid
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "strings"
+)
+
+// Header is the W3C Trace Context header a trace ID is taken from.
+const Header = "traceparent"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+    return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the trace ID in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+    id, _ := ctx.Value(contextKey{}).(string)
+    return id
+}
+
+// New returns a random 32 hex digit trace ID.
+func New() string {
+    var b [16]byte
+    _, _ = rand.Read(b[:])
+    return hex.EncodeToString(b[:])
+}
+
+// Parse extracts the trace ID from a traceparent header value of the form
+// "version-traceid-parentid-flags". It reports false for a malformed header
+// or the all-zero ID, in which case the caller should start a new trace.
+func Parse(traceparent string) (string, bool) {
+    parts := strings.Split(strings.TrimSpace(traceparent), "-")
+    if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+        return "", false
+    }
+    id := strings.ToLower(parts[1])
+    if len(id) != 32 || strings.Trim(id, "0") == "" {
+        return "", false
+    }
+    if _, err := hex.DecodeString(id); err != nil {
+        return "", false
+    }
+    return id, true
+}
+
+// FromHeader returns the trace ID of an incoming traceparent header, or a new
+// one when the header is missing or invalid.
+func FromHeader(traceparent string) string {
+    if id, ok := Parse(traceparent); ok {
+        return id
+    }
+    return New()
+}
@@ -0,0 +1,100 @@
+package version
+
+import (
+    "fmt"
+    "runtime"
+    "runtime/debug"
+    "strconv"
+    
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Set at build time through -ldflags "-X", as the Dockerfile does. Anything
+// left empty is taken from the VCS stamp Go embeds in the binary, where
+// BuildTime falls back to the commit time.
+var (
+    Version   string
+    Commit    string
+    BuildTime string
+    // Dirty is "true" when built from a modified work tree
+    Dirty string
+)
+
+// Info describes the running build.
+type Info struct {
+    Version   string `json:"version"`
+    Commit    string `json:"commit"`
+    BuildTime string `json:"build_time"`
+    Dirty     bool   `json:"dirty"`
+    GoVersion string `json:"go_version"`
+}
+
+var info = read()
+
+var buildInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+    Name: "build_info",
+    Help: "Always 1, labelled with the version, commit and time the binary was built from.",
+}, []string{"version", "commit", "dirty", "build_time", "go_version"})
+
+func init() {
+    buildInfo.WithLabelValues(info.Version, info.Commit, strconv.FormatBool(info.Dirty), info.BuildTime, info.GoVersion).Set(1)
+}
+
+// Get returns the build information.
+func Get() Info {
+    return info
+}
+
+// String formats the build information for --version.
+func (i Info) String() string {
+    commit := i.Commit
+    if i.Dirty {
+        commit += "-dirty"
+    }
+    return fmt.Sprintf("%s (commit %s, built %s, %s)", i.Version, commit, i.BuildTime, i.GoVersion)
+}
+
+func read() Info {
+    i := Info{
+        Version:   Version,
+        Commit:    Commit,
+        BuildTime: BuildTime,
+        GoVersion: runtime.Version(),
+    }
+    dirty, dirtyErr := strconv.ParseBool(Dirty)
+    i.Dirty = dirty
+    
+    if build, ok := debug.ReadBuildInfo(); ok {
+        if i.Version == "" && build.Main.Version != "(devel)" {
+            i.Version = build.Main.Version
+        }
+        for _, setting := range build.Settings {
+            switch setting.Key {
+            case "vcs.revision":
+                if i.Commit == "" {
+                    i.Commit = setting.Value
+                }
+            case "vcs.time":
+                if i.BuildTime == "" {
+                    i.BuildTime = setting.Value
+                }
+            case "vcs.modified":
+                if dirtyErr != nil {
+                    i.Dirty = setting.Value == "true"
+                }
+            }
+        }
+    }
+    
+    if i.Version == "" {
+        i.Version = "dev"
+    }
+    if i.Commit == "" {
+        i.Commit = "unknown"
+    }
+    if i.BuildTime == "" {
+        i.BuildTime = "unknown"
+    }
+    return i
+}
@@ -0,0 +1,220 @@
+package webhook
+
+import (
+    "bytes"
+    "context"
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math/rand"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+)
+
+const (
+    StatusPending   = "pending"
+    StatusSucceeded = "succeeded"
+    StatusDead      = "dead"
+)
+
+type Config struct {
+    MaxAttempts  int
+    BaseBackoff  time.Duration
+    MaxBackoff   time.Duration
+    PollInterval time.Duration
+    BatchSize    int32
+    Timeout      time.Duration
+}
+
+func DefaultConfig() Config {
+    return Config{
+        MaxAttempts:  8,
+        BaseBackoff:  30 * time.Second,
+        MaxBackoff:   time.Hour,
+        PollInterval: time.Second,
+        BatchSize:    20,
+        Timeout:      10 * time.Second,
+    }
+}
+
+// Dispatcher turns user events into webhook_deliveries rows and sends them,
+// retrying failures with exponential backoff until MaxAttempts is reached,
+// after which the delivery is dead-lettered.
+type Dispatcher struct {
+    repo   repository.WebhookRepository
+    client *http.Client
+    config Config
+    logger *zap.Logger
+}
+
+func NewDispatcher(repo repository.WebhookRepository, config Config, logger *zap.Logger) *Dispatcher {
+    return &Dispatcher{
+        repo:   repo,
+        client: &http.Client{Timeout: config.Timeout},
+        config: config,
+        logger: logger,
+    }
+}
+
+// Publish records one pending delivery per active subscription for the
+// event. The dispatcher is fed by the outbox relay rather than the broker so
+// that each event is enqueued once across all replicas, and deliveries are
+// keyed by the event ID so that publishing an event again after a failure
+// only adds the ones that are missing.
+func (d *Dispatcher) Publish(ctx context.Context, event events.Event) error {
+    subscriptions, err := d.repo.ListSubscriptionsForEvent(ctx, string(event.Type))
+    if err != nil {
+        return err
+    }
+    if len(subscriptions) == 0 {
+        return nil
+    }
+    
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    
+    for _, subscription := range subscriptions {
+        if _, err := d.repo.CreateDelivery(ctx, subscription.ID, event.ID, string(event.Type), payload); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+    ticker := time.NewTicker(d.config.PollInterval)
+    defer ticker.Stop()
+    
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            d.deliverDue(ctx)
+        }
+    }
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+    // Lease deliveries for longer than a send can take so that another
+    // replica does not pick them up concurrently
+    deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.config.Timeout+30*time.Second, d.config.BatchSize)
+    if err != nil {
+        if ctx.Err() == nil {
+            d.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+        }
+        return
+    }
+    
+    var wg sync.WaitGroup
+    for _, delivery := range deliveries {
+        wg.Add(1)
+        go func(delivery *db.WebhookDelivery) {
+            defer wg.Done()
+            d.deliver(ctx, delivery)
+        }(delivery)
+    }
+    wg.Wait()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *db.WebhookDelivery) {
+    subscription, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            d.fail(ctx, delivery, 0, "subscription no longer exists", true)
+            return
+        }
+        d.logger.Error("Failed to load webhook subscription", zap.Error(err), zap.Int64("delivery_id", delivery.ID))
+        return
+    }
+    if !subscription.Active {
+        d.fail(ctx, delivery, 0, "subscription is inactive", true)
+        return
+    }
+    
+    statusCode, err := d.send(ctx, subscription, delivery)
+    if err != nil {
+        d.fail(ctx, delivery, statusCode, err.Error(), false)
+        return
+    }
+    
+    if err := d.repo.MarkDeliverySucceeded(ctx, delivery.ID, statusCode); err != nil {
+        d.logger.Error("Failed to mark webhook delivery succeeded", zap.Error(err), zap.Int64("delivery_id", delivery.ID))
+    }
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription *db.WebhookSubscription, delivery *db.WebhookDelivery) (int, error) {
+    timestamp := time.Now().Unix()
+    
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+    if err != nil {
+        return 0, err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set(HeaderEvent, delivery.EventType)
+    req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+    if delivery.EventID.Valid {
+        req.Header.Set(HeaderEventID, strconv.FormatInt(delivery.EventID.Int64, 10))
+    }
+    req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+    req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+    
+    resp, err := d.client.Do(req)
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    _, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+    
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+    }
+    return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) fail(ctx context.Context, delivery *db.WebhookDelivery, statusCode int, reason string, permanent bool) {
+    attempt := int(delivery.Attempts) + 1
+    status := StatusPending
+    if permanent || attempt >= d.config.MaxAttempts {
+        status = StatusDead
+    }
+    nextAttemptAt := time.Now().Add(d.backoff(attempt))
+    
+    if err := d.repo.MarkDeliveryFailed(ctx, delivery.ID, status, nextAttemptAt, statusCode, reason); err != nil {
+        d.logger.Error("Failed to mark webhook delivery failed", zap.Error(err), zap.Int64("delivery_id", delivery.ID))
+        return
+    }
+    
+    d.logger.Warn("Webhook delivery failed",
+        zap.Int64("delivery_id", delivery.ID),
+        zap.Int32("subscription_id", delivery.SubscriptionID),
+        zap.Int("attempt", attempt),
+        zap.String("status", status),
+        zap.String("reason", reason),
+    )
+}
+
+// backoff doubles the delay for every attempt, capped at MaxBackoff, with up
+// to 20% jitter so retries from many deliveries do not line up.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+    delay := d.config.BaseBackoff
+    for i := 1; i < attempt && delay < d.config.MaxBackoff; i++ {
+        delay *= 2
+    }
+    if delay > d.config.MaxBackoff {
+        delay = d.config.MaxBackoff
+    }
+    return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
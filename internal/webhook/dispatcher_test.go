@@ -0,0 +1,181 @@
+package webhook
+
+import (
+    "context"
+    "database/sql"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "slices"
+    "strconv"
+    "testing"
+    "time"
+    
+    "go.uber.org/zap"
+    
+    "github.com/adityaK87/go-backend-assignment/db/sqlc/generated"
+    "github.com/adityaK87/go-backend-assignment/internal/events"
+    "github.com/adityaK87/go-backend-assignment/internal/repository"
+)
+
+// fakeRepository records what the dispatcher writes. Methods the tests do
+// not use panic through the nil embedded interface.
+type fakeRepository struct {
+    repository.WebhookRepository
+    
+    subscriptions []*db.WebhookSubscription
+    created       []int64
+    // states holds the status of every delivery marked
+    states []string
+}
+
+func (r *fakeRepository) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*db.WebhookSubscription, error) {
+    return r.subscriptions, nil
+}
+
+func (r *fakeRepository) GetSubscription(ctx context.Context, id int32) (*db.WebhookSubscription, error) {
+    for _, subscription := range r.subscriptions {
+        if subscription.ID == id {
+            return subscription, nil
+        }
+    }
+    return nil, sql.ErrNoRows
+}
+
+func (r *fakeRepository) CreateDelivery(ctx context.Context, subscriptionID int32, eventID int64, eventType string, payload []byte) (*db.WebhookDelivery, error) {
+    r.created = append(r.created, eventID)
+    return &db.WebhookDelivery{SubscriptionID: subscriptionID, EventType: eventType, Payload: payload}, nil
+}
+
+func (r *fakeRepository) MarkDeliverySucceeded(ctx context.Context, id int64, statusCode int) error {
+    r.states = append(r.states, StatusSucceeded)
+    return nil
+}
+
+func (r *fakeRepository) MarkDeliveryFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, statusCode int, lastError string) error {
+    r.states = append(r.states, status)
+    return nil
+}
+
+func TestBackoff(t *testing.T) {
+    d := NewDispatcher(nil, Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}, zap.NewNop())
+    
+    tests := []struct {
+        attempt int
+        want    time.Duration
+    }{
+        {1, time.Second},
+        {2, 2 * time.Second},
+        {4, 8 * time.Second},
+        {5, 10 * time.Second},
+        {30, 10 * time.Second},
+    }
+    
+    for _, tt := range tests {
+        t.Run(strconv.Itoa(tt.attempt), func(t *testing.T) {
+            // Jitter adds up to 20%
+            for range 50 {
+                got := d.backoff(tt.attempt)
+                if got < tt.want || got > tt.want+tt.want/5 {
+                    t.Fatalf("backoff(%d) = %v, want %v plus up to 20%%", tt.attempt, got, tt.want)
+                }
+            }
+        })
+    }
+}
+
+func TestPublishKeysDeliveriesByEvent(t *testing.T) {
+    repo := &fakeRepository{subscriptions: []*db.WebhookSubscription{{ID: 1}, {ID: 2}}}
+    d := NewDispatcher(repo, DefaultConfig(), zap.NewNop())
+    
+    if err := d.Publish(context.Background(), events.Event{ID: 42, Type: events.UserCreated, UserID: 7}); err != nil {
+        t.Fatalf("Publish: %v", err)
+    }
+    if want := []int64{42, 42}; !slices.Equal(repo.created, want) {
+        t.Errorf("deliveries created for events %v, want %v", repo.created, want)
+    }
+}
+
+func TestDeliver(t *testing.T) {
+    const secret = "whsec_test"
+    
+    tests := []struct {
+        name      string
+        status    int
+        attempts  int32
+        inactive  bool
+        missing   bool
+        wantSent  bool
+        wantState []string
+    }{
+        {name: "success", status: http.StatusOK, wantSent: true, wantState: []string{StatusSucceeded}},
+        {name: "failure is retried", status: http.StatusInternalServerError, wantSent: true, wantState: []string{StatusPending}},
+        {name: "last attempt is dead-lettered", status: http.StatusInternalServerError, attempts: 2, wantSent: true, wantState: []string{StatusDead}},
+        {name: "inactive subscription", status: http.StatusOK, inactive: true, wantState: []string{StatusDead}},
+        {name: "deleted subscription", status: http.StatusOK, missing: true, wantState: []string{StatusDead}},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            type request struct {
+                header http.Header
+                body   []byte
+            }
+            requests := make(chan request, 1)
+            server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                body, _ := io.ReadAll(r.Body)
+                requests <- request{r.Header, body}
+                w.WriteHeader(tt.status)
+            }))
+            defer server.Close()
+            
+            repo := &fakeRepository{}
+            if !tt.missing {
+                repo.subscriptions = []*db.WebhookSubscription{{ID: 1, Url: server.URL, Secret: secret, Active: !tt.inactive}}
+            }
+            config := DefaultConfig()
+            config.MaxAttempts = 3
+            d := NewDispatcher(repo, config, zap.NewNop())
+            
+            delivery := &db.WebhookDelivery{
+                ID:             5,
+                SubscriptionID: 1,
+                EventID:        sql.NullInt64{Int64: 42, Valid: true},
+                EventType:      string(events.UserCreated),
+                Payload:        []byte(`{"id":42}`),
+                Attempts:       tt.attempts,
+            }
+            d.deliver(context.Background(), delivery)
+            
+            if !slices.Equal(repo.states, tt.wantState) {
+                t.Errorf("marked %v, want %v", repo.states, tt.wantState)
+            }
+            
+            var received request
+            select {
+            case received = <-requests:
+            default:
+            }
+            if sent := received.header != nil; sent != tt.wantSent {
+                t.Fatalf("sent = %v, want %v", sent, tt.wantSent)
+            }
+            if !tt.wantSent {
+                return
+            }
+            
+            if got := received.header.Get(HeaderEventID); got != "42" {
+                t.Errorf("%s = %q, want 42", HeaderEventID, got)
+            }
+            if got := received.header.Get(HeaderDelivery); got != "5" {
+                t.Errorf("%s = %q, want 5", HeaderDelivery, got)
+            }
+            timestamp, err := strconv.ParseInt(received.header.Get(HeaderTimestamp), 10, 64)
+            if err != nil {
+                t.Fatalf("%s: %v", HeaderTimestamp, err)
+            }
+            if !Verify(secret, received.header.Get(HeaderSignature), timestamp, received.body, time.Minute, time.Now()) {
+                t.Error("signature does not verify")
+            }
+        })
+    }
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "strconv"
+    "time"
+)
+
+const (
+    HeaderEvent    = "X-Webhook-Event"
+    HeaderDelivery = "X-Webhook-Delivery"
+    // HeaderEventID carries the event's ID, the same for every delivery of
+    // the event, so receivers can drop duplicates
+    HeaderEventID   = "X-Webhook-Event-Id"
+    HeaderTimestamp = "X-Webhook-Timestamp"
+    HeaderSignature = "X-Webhook-Signature"
+    
+    signaturePrefix = "sha256="
+)
+
+// Sign computes the signature sent in HeaderSignature. The timestamp is part
+// of the signed content so receivers can reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+    mac.Write([]byte("."))
+    mac.Write(body)
+    return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature the way receivers are expected to, rejecting
+// timestamps further than tolerance from now.
+func Verify(secret, signature string, timestamp int64, body []byte, tolerance time.Duration, now time.Time) bool {
+    skew := now.Sub(time.Unix(timestamp, 0))
+    if skew < -tolerance || skew > tolerance {
+        return false
+    }
+    return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
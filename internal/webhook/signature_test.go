@@ -0,0 +1,39 @@
+package webhook
+
+import (
+    "testing"
+    "time"
+)
+
+func TestVerify(t *testing.T) {
+    const secret = "whsec_test"
+    now := time.Unix(1700000000, 0)
+    body := []byte(`{"type":"user.created","user_id":1}`)
+    signature := Sign(secret, now.Unix(), body)
+    
+    tests := []struct {
+        name      string
+        secret    string
+        signature string
+        timestamp int64
+        body      []byte
+        want      bool
+    }{
+        {"valid", secret, signature, now.Unix(), body, true},
+        {"wrong secret", "other", signature, now.Unix(), body, false},
+        {"tampered body", secret, signature, now.Unix(), []byte(`{"type":"user.deleted","user_id":1}`), false},
+        {"tampered timestamp", secret, signature, now.Unix() - 1, body, false},
+        {"missing prefix", secret, signature[len(signaturePrefix):], now.Unix(), body, false},
+        {"old within tolerance", secret, Sign(secret, now.Add(-4*time.Minute).Unix(), body), now.Add(-4 * time.Minute).Unix(), body, true},
+        {"too old", secret, Sign(secret, now.Add(-6*time.Minute).Unix(), body), now.Add(-6 * time.Minute).Unix(), body, false},
+        {"too far ahead", secret, Sign(secret, now.Add(6*time.Minute).Unix(), body), now.Add(6 * time.Minute).Unix(), body, false},
+    }
+    
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, 5*time.Minute, now); got != tt.want {
+                t.Errorf("Verify() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}